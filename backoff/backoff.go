@@ -0,0 +1,62 @@
+// Package backoff retries operations with configurable delays between
+// attempts. All waiting goes through clock.From(ctx), so retry policies can be
+// tested instantly with clock.TimeTravel or clock.Fake.
+package backoff
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff computes the delay before a retry.
+type Backoff interface {
+	// Delay returns how long to wait before the given retry. The first retry
+	// is attempt 1. prev is the delay returned for the previous retry, or 0.
+	Delay(attempt int, prev time.Duration) time.Duration
+}
+
+// Func adapts a function to a Backoff.
+type Func func(attempt int, prev time.Duration) time.Duration
+
+func (f Func) Delay(attempt int, prev time.Duration) time.Duration {
+	return f(attempt, prev)
+}
+
+// Constant returns a Backoff that always waits d.
+func Constant(d time.Duration) Backoff {
+	return Func(func(int, time.Duration) time.Duration {
+		return d
+	})
+}
+
+// Exponential returns a Backoff that waits initial before the first retry and
+// multiplies the delay by factor for each subsequent retry, up to max.
+func Exponential(initial, max time.Duration, factor float64) Backoff {
+	return Func(func(attempt int, _ time.Duration) time.Duration {
+		d := float64(initial) * math.Pow(factor, float64(attempt-1))
+		if d > float64(max) {
+			return max
+		}
+		return time.Duration(d)
+	})
+}
+
+// DecorrelatedJitter returns a Backoff that waits a random duration between
+// base and three times the previous delay, up to max. See
+// https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/.
+func DecorrelatedJitter(base, max time.Duration) Backoff {
+	return Func(func(_ int, prev time.Duration) time.Duration {
+		if prev < base {
+			prev = base
+		}
+		upper := 3 * prev
+		if upper > max {
+			upper = max
+		}
+		if upper <= base {
+			return upper
+		}
+		return base + time.Duration(rand.Int63n(int64(upper-base))) //nolint:gosec
+	})
+}
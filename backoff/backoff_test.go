@@ -0,0 +1,50 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConstant(t *testing.T) {
+	t.Parallel()
+
+	b := Constant(time.Second)
+	assert.Equal(t, time.Second, b.Delay(1, 0))
+	assert.Equal(t, time.Second, b.Delay(10, time.Second))
+}
+
+func TestExponential(t *testing.T) {
+	t.Parallel()
+
+	b := Exponential(time.Second, time.Minute, 2)
+	var prev time.Duration
+	var delays []time.Duration
+	for attempt := 1; attempt <= 8; attempt++ {
+		prev = b.Delay(attempt, prev)
+		delays = append(delays, prev)
+	}
+	assert.Equal(t, []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second,
+		16 * time.Second, 32 * time.Second, time.Minute, time.Minute,
+	}, delays)
+	assert.Equal(t, time.Minute, b.Delay(1000, 0))
+}
+
+func TestDecorrelatedJitter(t *testing.T) {
+	t.Parallel()
+
+	b := DecorrelatedJitter(time.Second, time.Minute)
+	var prev time.Duration
+	for attempt := 1; attempt <= 100; attempt++ {
+		d := b.Delay(attempt, prev)
+		assert.GreaterOrEqual(t, d, time.Second)
+		assert.LessOrEqual(t, d, time.Minute)
+		if prev > 0 {
+			assert.LessOrEqual(t, d, 3*prev)
+		}
+		prev = d
+	}
+	assert.Equal(t, time.Second, DecorrelatedJitter(time.Second, time.Second).Delay(1, 0))
+}
@@ -0,0 +1,103 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/anz-bank/pkg/clock"
+)
+
+type retryOptions struct {
+	maxAttempts    int
+	maxElapsedTime time.Duration
+	retryable      func(error) bool
+}
+
+// Option is used to configure Retry. The With* functions should be used to
+// obtain options for passing to Retry.
+type Option func(*retryOptions)
+
+// WithMaxAttempts returns an Option that limits the total number of attempts,
+// including the first. Values less than 1 mean no limit, which is the
+// default.
+func WithMaxAttempts(n int) Option {
+	return func(ro *retryOptions) {
+		ro.maxAttempts = n
+	}
+}
+
+// WithMaxElapsedTime returns an Option that stops retrying once waiting for
+// the next attempt would take the total time past d, as measured on the
+// context clock. Zero means no limit, which is the default.
+func WithMaxElapsedTime(d time.Duration) Option {
+	return func(ro *retryOptions) {
+		ro.maxElapsedTime = d
+	}
+}
+
+// WithRetryable returns an Option that classifies errors. Errors for which
+// retryable returns false are returned immediately. By default every error
+// is retried except those marked with Permanent.
+func WithRetryable(retryable func(error) bool) Option {
+	return func(ro *retryOptions) {
+		ro.retryable = retryable
+	}
+}
+
+// Retry calls op until it succeeds, returns a non-retryable error, or a limit
+// is reached, waiting on the context clock between attempts according to b.
+// It returns nil on success, ctx.Err() if ctx is done while waiting, and the
+// error from the last attempt otherwise. Permanent errors are unwrapped.
+func Retry(ctx context.Context, b Backoff, op func(context.Context) error, options ...Option) error {
+	ro := &retryOptions{retryable: func(error) bool { return true }}
+	for _, option := range options {
+		option(ro)
+	}
+
+	start := clock.Now(ctx)
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		err := op(ctx)
+		if err == nil {
+			return nil
+		}
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+		if !ro.retryable(err) {
+			return err
+		}
+		if ro.maxAttempts > 0 && attempt >= ro.maxAttempts {
+			return err
+		}
+		delay = b.Delay(attempt, delay)
+		if ro.maxElapsedTime > 0 && clock.Since(ctx, start)+delay > ro.maxElapsedTime {
+			return err
+		}
+		if err := clock.SleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// Permanent wraps err to tell Retry not to retry it. Retry returns err itself.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,109 @@
+package backoff
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anz-bank/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+var errFlaky = errors.New("flaky")
+
+// failing returns an operation that fails n times before succeeding, and a
+// pointer to the number of calls made.
+func failing(n int) (func(context.Context) error, *int) {
+	calls := 0
+	return func(context.Context) error {
+		calls++
+		if calls <= n {
+			return errFlaky
+		}
+		return nil
+	}, &calls
+}
+
+func timeTravel(t *testing.T) context.Context {
+	tt := clock.NewTimeTravel(0)
+	t.Cleanup(tt.Close)
+	return clock.Onto(context.Background(), tt)
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	ctx := timeTravel(t)
+	t0 := clock.Now(ctx)
+	realT0 := time.Now()
+
+	op, calls := failing(10)
+	assert.NoError(t, Retry(ctx, Constant(time.Minute), op))
+	assert.Equal(t, 11, *calls)
+
+	// Ten minutes of retrying should take no real time at all.
+	assert.GreaterOrEqual(t, clock.Since(ctx, t0), 10*time.Minute)
+	assert.Less(t, time.Since(realT0), time.Second)
+}
+
+func TestRetryMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	ctx := timeTravel(t)
+	op, calls := failing(10)
+	assert.Equal(t, errFlaky, Retry(ctx, Constant(time.Minute), op, WithMaxAttempts(3)))
+	assert.Equal(t, 3, *calls)
+}
+
+func TestRetryMaxElapsedTime(t *testing.T) {
+	t.Parallel()
+
+	ctx := timeTravel(t)
+	op, calls := failing(10)
+	err := Retry(ctx, Exponential(time.Minute, time.Hour, 2), op, WithMaxElapsedTime(10*time.Minute))
+	assert.Equal(t, errFlaky, err)
+	// Waits of 1m, 2m and 4m fit. The next wait of 8m would not.
+	assert.Equal(t, 4, *calls)
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	t.Parallel()
+
+	ctx := timeTravel(t)
+	op, calls := failing(10)
+	err := Retry(ctx, Constant(time.Minute), op, WithRetryable(func(err error) bool {
+		return !errors.Is(err, errFlaky)
+	}))
+	assert.Equal(t, errFlaky, err)
+	assert.Equal(t, 1, *calls)
+}
+
+func TestRetryPermanent(t *testing.T) {
+	t.Parallel()
+
+	ctx := timeTravel(t)
+	calls := 0
+	err := Retry(ctx, Constant(time.Minute), func(context.Context) error {
+		calls++
+		return Permanent(errFlaky)
+	})
+	assert.Equal(t, errFlaky, err)
+	assert.Equal(t, 1, calls)
+	assert.Nil(t, Permanent(nil))
+	assert.Equal(t, "flaky", Permanent(errFlaky).Error())
+}
+
+func TestRetryCancelled(t *testing.T) {
+	t.Parallel()
+
+	f := clock.NewFake(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithCancel(clock.Onto(context.Background(), f))
+	op, calls := failing(10)
+	errs := make(chan error)
+	go func() { errs <- Retry(ctx, Constant(time.Minute), op) }()
+	assert.NoError(t, f.WaitForWaiters(ctx, 1))
+	cancel()
+	assert.Equal(t, context.Canceled, <-errs)
+	assert.Equal(t, 1, *calls)
+}
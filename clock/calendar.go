@@ -0,0 +1,127 @@
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// InLocation sets the context clock to one that reports times in loc. The
+// calendar helpers in this package use the same location. A nil loc means
+// UTC.
+func InLocation(ctx context.Context, loc *time.Location) context.Context {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return Onto(ctx, locatedClock{Clock: From(ctx), loc: loc})
+}
+
+// Location returns the location of the context clock. Clocks may specify one
+// by implementing a Location() *time.Location method, as clocks set with
+// InLocation do. Otherwise it is time.Local.
+func Location(ctx context.Context) *time.Location {
+	if l, ok := From(ctx).(interface{ Location() *time.Location }); ok {
+		return l.Location()
+	}
+	return time.Local
+}
+
+type locatedClock struct {
+	Clock
+	loc *time.Location
+}
+
+func (c locatedClock) Location() *time.Location {
+	return c.loc
+}
+
+func (c locatedClock) Now() time.Time {
+	return c.Clock.Now().In(c.loc)
+}
+
+// Calendar describes business days and hours. Weekends are never business
+// days.
+type Calendar struct {
+	// Open and Close are the wall-clock times of day that business hours start
+	// and end, as offsets from midnight.
+	Open, Close time.Duration
+	// Holidays are additional days that are not business days. Only their
+	// dates are significant.
+	Holidays []time.Time
+}
+
+// DefaultCalendar has business hours from 9am to 5pm and no holidays.
+var DefaultCalendar = Calendar{Open: 9 * time.Hour, Close: 17 * time.Hour}
+
+// IsBusinessDay reports whether t falls on a business day in t's location.
+func (c Calendar) IsBusinessDay(t time.Time) bool {
+	if wd := t.Weekday(); wd == time.Saturday || wd == time.Sunday {
+		return false
+	}
+	y, m, d := t.Date()
+	for _, h := range c.Holidays {
+		if hy, hm, hd := h.Date(); hy == y && hm == m && hd == d {
+			return false
+		}
+	}
+	return true
+}
+
+// NextBusinessDay returns the start of the first business day after the day
+// of t.
+func (c Calendar) NextBusinessDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	for i := 1; ; i++ {
+		next := time.Date(y, m, d+i, 0, 0, 0, 0, t.Location())
+		if c.IsBusinessDay(next) {
+			return next
+		}
+	}
+}
+
+// InBusinessHours reports whether t is on a business day and between the
+// opening and closing times.
+func (c Calendar) InBusinessHours(t time.Time) bool {
+	if !c.IsBusinessDay(t) {
+		return false
+	}
+	y, m, d := t.Date()
+	open := time.Date(y, m, d, 0, 0, 0, int(c.Open), t.Location())
+	closing := time.Date(y, m, d, 0, 0, 0, int(c.Close), t.Location())
+	return !t.Before(open) && t.Before(closing)
+}
+
+// StartOfDay returns midnight at the start of t's day in t's location.
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// The following functions evaluate the current time of the context clock in
+// its Location.
+
+// LocalNow returns Now(ctx) in Location(ctx).
+func LocalNow(ctx context.Context) time.Time {
+	return Now(ctx).In(Location(ctx))
+}
+
+// Today returns the start of the current day.
+func Today(ctx context.Context) time.Time {
+	return StartOfDay(LocalNow(ctx))
+}
+
+// IsBusinessDay reports whether today is a business day in DefaultCalendar.
+func IsBusinessDay(ctx context.Context) bool {
+	return DefaultCalendar.IsBusinessDay(LocalNow(ctx))
+}
+
+// NextBusinessDay returns the start of the next business day in
+// DefaultCalendar.
+func NextBusinessDay(ctx context.Context) time.Time {
+	return DefaultCalendar.NextBusinessDay(LocalNow(ctx))
+}
+
+// InBusinessHours reports whether it is currently business hours in
+// DefaultCalendar.
+func InBusinessHours(ctx context.Context) bool {
+	return DefaultCalendar.InBusinessHours(LocalNow(ctx))
+}
@@ -0,0 +1,63 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInLocation(t *testing.T) {
+	t.Parallel()
+
+	melbourne, err := time.LoadLocation("Australia/Melbourne")
+	require.NoError(t, err)
+
+	// Friday 2020-01-03 at 22:00 UTC is Saturday 09:00 in Melbourne.
+	f := NewFake(time.Date(2020, time.January, 3, 22, 0, 0, 0, time.UTC))
+	ctx := Onto(context.Background(), f)
+	assert.Equal(t, time.Local, Location(ctx))
+
+	ctx = InLocation(ctx, melbourne)
+	assert.Equal(t, melbourne, Location(ctx))
+	assert.Equal(t, melbourne, Now(ctx).Location())
+	assert.Equal(t, time.Date(2020, time.January, 4, 0, 0, 0, 0, melbourne), Today(ctx))
+	assert.False(t, IsBusinessDay(ctx))
+	assert.False(t, InBusinessHours(ctx))
+	assert.Equal(t, time.Date(2020, time.January, 6, 0, 0, 0, 0, melbourne), NextBusinessDay(ctx))
+
+	// Monday 09:00 in Melbourne.
+	f.Advance(48 * time.Hour)
+	assert.True(t, IsBusinessDay(ctx))
+	assert.True(t, InBusinessHours(ctx))
+
+	ctx = InLocation(ctx, nil)
+	assert.Equal(t, time.UTC, Location(ctx))
+	assert.Equal(t, time.UTC, Now(ctx).Location())
+}
+
+func TestCalendar(t *testing.T) {
+	t.Parallel()
+
+	melbourne, err := time.LoadLocation("Australia/Melbourne")
+	require.NoError(t, err)
+	c := Calendar{
+		Open:     9 * time.Hour,
+		Close:    17*time.Hour + 30*time.Minute,
+		Holidays: []time.Time{time.Date(2020, time.December, 25, 0, 0, 0, 0, time.UTC)},
+	}
+
+	christmasEve := time.Date(2020, time.December, 24, 17, 29, 0, 0, melbourne)
+	assert.True(t, c.InBusinessHours(christmasEve))
+	assert.False(t, c.InBusinessHours(christmasEve.Add(time.Minute)))
+	assert.False(t, c.InBusinessHours(time.Date(2020, time.December, 24, 8, 59, 0, 0, melbourne)))
+	assert.False(t, c.IsBusinessDay(time.Date(2020, time.December, 25, 12, 0, 0, 0, melbourne)))
+	assert.Equal(t, time.Date(2020, time.December, 28, 0, 0, 0, 0, melbourne), c.NextBusinessDay(christmasEve))
+
+	// Business hours are wall-clock times, even when daylight saving starts.
+	dst := time.Date(2020, time.October, 5, 9, 0, 0, 0, melbourne)
+	assert.True(t, c.InBusinessHours(dst))
+	assert.Equal(t, time.Date(2020, time.October, 4, 0, 0, 0, 0, melbourne), StartOfDay(dst.Add(-24*time.Hour)))
+}
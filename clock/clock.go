@@ -80,3 +80,39 @@ func Sleep(ctx context.Context, d time.Duration) {
 func Until(ctx context.Context, t time.Time) time.Duration {
 	return t.Sub(From(ctx).Now())
 }
+
+// The following functions are like their counterparts above, but give up
+// waiting when the context is done.
+
+// AfterContext is like After, but closes the returned channel without sending
+// anything if ctx is done before the duration elapses.
+func AfterContext(ctx context.Context, d time.Duration) <-chan time.Time {
+	ch := make(chan time.Time, 1)
+	timer := From(ctx).NewTimer(d)
+	go func() {
+		defer close(ch)
+		select {
+		case t := <-timer.C():
+			ch <- t
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}()
+	return ch
+}
+
+// SleepContext is like Sleep, but returns ctx.Err() if ctx is done before the
+// duration elapses.
+func SleepContext(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	timer := From(ctx).NewTimer(d)
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		timer.Stop()
+		return ctx.Err()
+	}
+}
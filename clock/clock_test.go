@@ -109,3 +109,54 @@ func TestClockTimerReset(t *testing.T) {
 	assert.False(t, timer.Stop())
 	assert.InEpsilon(t, float64(t1.Sub(t0)), float64(400*time.Millisecond), float64(20*time.Millisecond))
 }
+
+func TestSleepContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, SleepContext(ctx, time.Millisecond))
+	cancel()
+	assert.Equal(t, context.Canceled, SleepContext(ctx, time.Hour))
+}
+
+func TestSleepContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	ctx, cancel := context.WithCancel(Onto(context.Background(), f))
+	errs := make(chan error)
+	go func() { errs <- SleepContext(ctx, time.Hour) }()
+	cancel()
+	assert.Equal(t, context.Canceled, <-errs)
+}
+
+func TestAfterContext(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	ctx, cancel := context.WithCancel(Onto(context.Background(), f))
+	defer cancel()
+
+	ch := AfterContext(ctx, time.Minute)
+	f.Advance(time.Minute)
+	now, ok := <-ch
+	assert.True(t, ok)
+	assert.Equal(t, fakeEpoch.Add(time.Minute), now)
+
+	ch = AfterContext(ctx, time.Minute)
+	cancel()
+	_, ok = <-ch
+	assert.False(t, ok)
+}
+
+func TestSleepContextTimeTravel(t *testing.T) {
+	t.Parallel()
+
+	tt := NewTimeTravel(0)
+	defer tt.Close()
+	ctx := Onto(context.Background(), tt)
+	t0 := Now(ctx)
+
+	assert.NoError(t, SleepContext(ctx, time.Hour))
+	assertWithinOneSecond(t, t0.Add(time.Hour), Now(ctx))
+}
@@ -0,0 +1,83 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithDeadline is like context.WithDeadline, but the deadline is measured on
+// the context Clock rather than the system clock.
+func WithDeadline(ctx context.Context, d time.Time) (context.Context, context.CancelFunc) {
+	if cur, ok := ctx.Deadline(); ok && cur.Before(d) {
+		// The current deadline is already sooner than the new one.
+		return context.WithCancel(ctx)
+	}
+	c := &deadlineCtx{Context: ctx, deadline: d, done: make(chan struct{})}
+	timer := From(ctx).AfterFunc(d.Sub(From(ctx).Now()), func() {
+		c.cancel(context.DeadlineExceeded)
+	})
+	c.mux.Lock()
+	c.timer = timer
+	cancelled := c.err != nil
+	c.mux.Unlock()
+	if cancelled {
+		timer.Stop()
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.cancel(ctx.Err())
+		case <-c.done:
+		}
+	}()
+	return c, func() { c.cancel(context.Canceled) }
+}
+
+// WithTimeout returns WithDeadline(ctx, Now(ctx).Add(timeout)).
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return WithDeadline(ctx, Now(ctx).Add(timeout))
+}
+
+// deadlineCtx implements its own cancellation rather than wrapping a
+// context.WithCancel so that contexts derived from it see DeadlineExceeded.
+type deadlineCtx struct {
+	context.Context
+	deadline time.Time
+	timer    Timer
+	done     chan struct{}
+
+	mux sync.Mutex
+	err error
+}
+
+func (c *deadlineCtx) cancel(err error) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	close(c.done)
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+}
+
+func (c *deadlineCtx) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c *deadlineCtx) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *deadlineCtx) Err() error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.err
+}
+
+func (c *deadlineCtx) String() string {
+	return "clock.WithDeadline(" + c.deadline.String() + ")"
+}
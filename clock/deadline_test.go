@@ -0,0 +1,81 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTimeout(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	ctx, cancel := WithTimeout(Onto(context.Background(), f), time.Minute)
+	defer cancel()
+
+	deadline, ok := ctx.Deadline()
+	assert.True(t, ok)
+	assert.Equal(t, fakeEpoch.Add(time.Minute), deadline)
+
+	f.Advance(59 * time.Second)
+	assert.NoError(t, ctx.Err())
+	f.Advance(time.Second)
+	<-ctx.Done()
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
+
+func TestWithDeadlineCancel(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	ctx, cancel := WithDeadline(Onto(context.Background(), f), fakeEpoch.Add(time.Minute))
+	cancel()
+	<-ctx.Done()
+	assert.Equal(t, context.Canceled, ctx.Err())
+	f.Advance(time.Hour)
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
+
+func TestWithDeadlinePast(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := WithDeadline(Onto(context.Background(), NewFake(fakeEpoch)), fakeEpoch)
+	defer cancel()
+	<-ctx.Done()
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
+
+func TestWithDeadlineParentSooner(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	parent, cancel := WithTimeout(Onto(context.Background(), f), time.Minute)
+	defer cancel()
+	ctx, cancel := WithTimeout(parent, time.Hour)
+	defer cancel()
+
+	deadline, _ := ctx.Deadline()
+	assert.Equal(t, fakeEpoch.Add(time.Minute), deadline)
+	f.Advance(time.Minute)
+	<-ctx.Done()
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
+
+func TestWithTimeoutTimeTravel(t *testing.T) {
+	t.Parallel()
+
+	tt := NewTimeTravel(0)
+	defer tt.Close()
+	ctx, cancel := WithTimeout(Onto(context.Background(), tt), time.Hour)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+	case <-After(ctx, 2*time.Hour):
+		t.Fatal("deadline should expire before a later waiter")
+	}
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+	assert.Equal(t, context.DeadlineExceeded, SleepContext(ctx, time.Hour))
+}
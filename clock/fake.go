@@ -0,0 +1,259 @@
+package clock
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fake is a Clock whose time only moves when it is explicitly advanced with
+// Advance or Set. Timers, tickers and AfterFunc callbacks fire in deadline
+// order as time moves past them. Channel sends follow the time package: each
+// channel has a buffer of one and a tick is dropped if the buffer is full.
+// AfterFunc callbacks run synchronously on the goroutine advancing the clock.
+// Waiters created with a non-positive duration fire immediately.
+type Fake struct {
+	mux      sync.Mutex
+	now      time.Time
+	waiters  []*fakeWaiter
+	blockers []waiterBlocker
+}
+
+var _ Clock = &Fake{}
+
+// NewFake creates a Fake Clock reporting the given time.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Advance moves the clock forward by d, firing every waiter whose deadline is
+// reached along the way. Negative durations are treated as 0.
+func (f *Fake) Advance(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	f.mux.Lock()
+	target := f.now.Add(d)
+	f.mux.Unlock()
+	f.advanceTo(target)
+}
+
+// Set moves the clock to t. If t is after the current time, waiters fire just
+// as they would for Advance. If t is before the current time, the clock steps
+// backwards without firing anything.
+func (f *Fake) Set(t time.Time) {
+	f.mux.Lock()
+	if t.Before(f.now) {
+		f.now = t
+		f.mux.Unlock()
+		return
+	}
+	f.mux.Unlock()
+	f.advanceTo(t)
+}
+
+func (f *Fake) advanceTo(target time.Time) {
+	for {
+		f.mux.Lock()
+		if len(f.waiters) == 0 || f.waiters[0].deadline.After(target) {
+			if f.now.Before(target) {
+				f.now = target
+			}
+			f.mux.Unlock()
+			return
+		}
+		w := f.waiters[0]
+		f.waiters = f.waiters[1:]
+		if f.now.Before(w.deadline) {
+			f.now = w.deadline
+		}
+		now := f.now
+		if w.period > 0 {
+			w.deadline = now.Add(w.period)
+			f.schedule(w)
+		} else {
+			w.active = false
+		}
+		f.mux.Unlock()
+		w.fire(now)
+	}
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	return f.newTimer(d, nil, fn)
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock.Fake.NewTicker: non-positive interval")
+	}
+	w := &fakeWaiter{clock: f, ch: make(chan time.Time, 1), period: d}
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	w.deadline = f.now.Add(d)
+	f.schedule(w)
+	return fakeTicker{w}
+}
+
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	return f.newTimer(d, make(chan time.Time, 1), nil)
+}
+
+func (f *Fake) Now() time.Time {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return f.now
+}
+
+// Waiters returns the deadlines of all pending timers, tickers and AfterFunc
+// callbacks in the order they will fire.
+func (f *Fake) Waiters() []time.Time {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	deadlines := make([]time.Time, 0, len(f.waiters))
+	for _, w := range f.waiters {
+		deadlines = append(deadlines, w.deadline)
+	}
+	return deadlines
+}
+
+// WaitForWaiters blocks until at least n timers, tickers or AfterFunc
+// callbacks are pending or ctx is done. Tests can use it to ensure the code
+// under test is parked on the clock before advancing it.
+func (f *Fake) WaitForWaiters(ctx context.Context, n int) error {
+	f.mux.Lock()
+	if len(f.waiters) >= n {
+		f.mux.Unlock()
+		return nil
+	}
+	ch := make(chan struct{})
+	f.blockers = append(f.blockers, waiterBlocker{n: n, ch: ch})
+	f.mux.Unlock()
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (f *Fake) newTimer(d time.Duration, ch chan time.Time, fn func()) *fakeTimer {
+	w := &fakeWaiter{clock: f, ch: ch, fn: fn}
+	f.mux.Lock()
+	w.deadline = f.now.Add(d)
+	f.schedule(w)
+	f.mux.Unlock()
+	if d <= 0 {
+		f.advanceTo(f.Now())
+	}
+	return &fakeTimer{w}
+}
+
+// schedule inserts w after any waiters with the same deadline, so that ties
+// fire in creation order. The caller must hold f.mux.
+func (f *Fake) schedule(w *fakeWaiter) {
+	i := sort.Search(len(f.waiters), func(i int) bool {
+		return f.waiters[i].deadline.After(w.deadline)
+	})
+	f.waiters = append(f.waiters, nil)
+	copy(f.waiters[i+1:], f.waiters[i:])
+	f.waiters[i] = w
+	w.active = true
+
+	blockers := f.blockers[:0]
+	for _, b := range f.blockers {
+		if len(f.waiters) >= b.n {
+			close(b.ch)
+		} else {
+			blockers = append(blockers, b)
+		}
+	}
+	f.blockers = blockers
+}
+
+// unschedule removes w and reports whether it was pending. The caller must
+// hold f.mux.
+func (f *Fake) unschedule(w *fakeWaiter) bool {
+	if !w.active {
+		return false
+	}
+	for i, v := range f.waiters {
+		if v == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			break
+		}
+	}
+	w.active = false
+	return true
+}
+
+type waiterBlocker struct {
+	n  int
+	ch chan struct{}
+}
+
+type fakeWaiter struct {
+	clock    *Fake
+	deadline time.Time
+	period   time.Duration
+	ch       chan time.Time
+	fn       func()
+	active   bool
+}
+
+func (w *fakeWaiter) fire(now time.Time) {
+	if w.fn != nil {
+		w.fn()
+		return
+	}
+	select {
+	case w.ch <- now:
+	default:
+	}
+}
+
+type fakeTicker struct {
+	w *fakeWaiter
+}
+
+func (t fakeTicker) C() <-chan time.Time {
+	return t.w.ch
+}
+
+func (t fakeTicker) Stop() {
+	t.w.clock.mux.Lock()
+	defer t.w.clock.mux.Unlock()
+	t.w.clock.unschedule(t.w)
+}
+
+type fakeTimer struct {
+	w *fakeWaiter
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.w.ch
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	f := t.w.clock
+	f.mux.Lock()
+	active := f.unschedule(t.w)
+	t.w.deadline = f.now.Add(d)
+	f.schedule(t.w)
+	f.mux.Unlock()
+	if d <= 0 {
+		f.advanceTo(f.Now())
+	}
+	return active
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.w.clock.mux.Lock()
+	defer t.w.clock.mux.Unlock()
+	return t.w.clock.unschedule(t.w)
+}
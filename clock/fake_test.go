@@ -0,0 +1,162 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var fakeEpoch = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFake(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	ctx := Onto(context.Background(), f)
+
+	assert.Equal(t, fakeEpoch, Now(ctx))
+	f.Advance(time.Hour)
+	assert.Equal(t, fakeEpoch.Add(time.Hour), Now(ctx))
+	f.Advance(-time.Hour)
+	assert.Equal(t, fakeEpoch.Add(time.Hour), Now(ctx))
+	f.Set(fakeEpoch)
+	assert.Equal(t, fakeEpoch, Now(ctx))
+	assert.Equal(t, time.Hour, Until(ctx, fakeEpoch.Add(time.Hour)))
+}
+
+func TestFakeAfter(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	ch := f.After(time.Minute)
+	f.Advance(59 * time.Second)
+	assert.Empty(t, ch)
+	f.Advance(2 * time.Second)
+	assert.Equal(t, fakeEpoch.Add(time.Minute), <-ch)
+	assert.Equal(t, fakeEpoch.Add(61*time.Second), f.Now())
+
+	assert.Equal(t, fakeEpoch.Add(61*time.Second), <-f.After(0))
+}
+
+func TestFakeDeadlineOrder(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	var fired []time.Duration
+	record := func(d time.Duration) func() {
+		return func() {
+			assert.Equal(t, fakeEpoch.Add(d), f.Now())
+			fired = append(fired, d)
+		}
+	}
+	f.AfterFunc(3*time.Second, record(3*time.Second))
+	f.AfterFunc(time.Second, record(time.Second))
+	f.AfterFunc(2*time.Second, record(2*time.Second))
+	f.Advance(time.Minute)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, fired)
+}
+
+func TestFakeAfterFuncReentrant(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	var fired []time.Time
+	f.AfterFunc(time.Second, func() {
+		fired = append(fired, f.Now())
+		f.AfterFunc(time.Second, func() { fired = append(fired, f.Now()) })
+	})
+	f.Advance(5 * time.Second)
+	assert.Equal(t, []time.Time{fakeEpoch.Add(time.Second), fakeEpoch.Add(2 * time.Second)}, fired)
+}
+
+func TestFakeTimer(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	timer := f.NewTimer(time.Second)
+	f.Advance(time.Second)
+	assert.False(t, timer.Stop())
+	assert.Equal(t, fakeEpoch.Add(time.Second), <-timer.C())
+
+	assert.False(t, timer.Reset(time.Second))
+	assert.True(t, timer.Stop())
+	f.Advance(time.Hour)
+	assert.Empty(t, timer.C())
+
+	assert.False(t, timer.Reset(time.Second))
+	assert.True(t, timer.Reset(2*time.Second))
+	f.Advance(time.Second)
+	assert.Empty(t, timer.C())
+	f.Advance(time.Second)
+	assert.Equal(t, fakeEpoch.Add(time.Hour+3*time.Second), <-timer.C())
+}
+
+func TestFakeTimerUnreadValueRemains(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	timer := f.NewTimer(time.Second)
+	f.Advance(time.Second)
+	assert.False(t, timer.Reset(time.Second))
+	f.Advance(time.Second)
+	assert.Equal(t, fakeEpoch.Add(time.Second), <-timer.C())
+	assert.Empty(t, timer.C())
+}
+
+func TestFakeAfterFuncStop(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	called := false
+	timer := f.AfterFunc(time.Second, func() { called = true })
+	assert.Nil(t, timer.C())
+	assert.True(t, timer.Stop())
+	f.Advance(time.Hour)
+	assert.False(t, called)
+}
+
+func TestFakeTicker(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	ticker := f.NewTicker(time.Second)
+	f.Advance(time.Second)
+	assert.Equal(t, fakeEpoch.Add(time.Second), <-ticker.C())
+	f.Advance(time.Second)
+	assert.Equal(t, fakeEpoch.Add(2*time.Second), <-ticker.C())
+
+	// Ticks are dropped for slow receivers.
+	f.Advance(5 * time.Second)
+	assert.Equal(t, fakeEpoch.Add(3*time.Second), <-ticker.C())
+	assert.Empty(t, ticker.C())
+
+	ticker.Stop()
+	f.Advance(time.Hour)
+	assert.Empty(t, ticker.C())
+
+	assert.Panics(t, func() { f.NewTicker(0) })
+}
+
+func TestFakeWaitForWaiters(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	ctx := Onto(context.Background(), f)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Sleep(ctx, time.Minute)
+	}()
+	assert.NoError(t, f.WaitForWaiters(ctx, 1))
+	assert.Equal(t, []time.Time{fakeEpoch.Add(time.Minute)}, f.Waiters())
+	f.Advance(time.Minute)
+	<-done
+	assert.Empty(t, f.Waiters())
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Equal(t, context.Canceled, f.WaitForWaiters(cctx, 1))
+}
@@ -0,0 +1,121 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Fault is an anomaly in the wall clock reported by a Faulty clock. Use Step,
+// Drift and Freeze to create Faults.
+type Fault struct {
+	// At is when the fault starts, measured on the wrapped clock from when the
+	// Faulty clock was created.
+	At time.Duration
+
+	step     time.Duration
+	rate     float64
+	duration time.Duration
+}
+
+// Step returns a Fault that jumps the clock by d at the given time, as when
+// NTP steps the clock. Negative values of d step the clock backwards.
+func Step(at, d time.Duration) Fault {
+	return Fault{At: at, step: d}
+}
+
+// Drift returns a Fault that makes the clock gain rate seconds per second
+// from the given time onwards. Negative rates make the clock lose time.
+func Drift(at time.Duration, rate float64) Fault {
+	return Fault{At: at, rate: rate}
+}
+
+// Freeze returns a Fault that stops the clock for d starting at the given
+// time. Once the clock resumes, it lags behind by d.
+func Freeze(at, d time.Duration) Fault {
+	return Fault{At: at, duration: d}
+}
+
+// offset returns the fault's contribution to the reported time once elapsed
+// has passed on the wrapped clock.
+func (f Fault) offset(elapsed time.Duration) time.Duration {
+	since := elapsed - f.At
+	if since < 0 {
+		return 0
+	}
+	switch {
+	case f.step != 0:
+		return f.step
+	case f.rate != 0:
+		return time.Duration(float64(since) * f.rate)
+	case f.duration > since:
+		return -since
+	default:
+		return -f.duration
+	}
+}
+
+// Faulty is a Clock that injects wall-clock anomalies into the times reported
+// by a wrapped Clock according to a script of Faults. Timers and tickers are
+// delegated to the wrapped Clock unchanged, just as the system's monotonic
+// timers are unaffected when the wall clock misbehaves. Reported times carry
+// no monotonic clock reading, so comparisons between them see the anomalies.
+type Faulty struct {
+	clock Clock
+	start time.Time
+
+	mux    sync.Mutex
+	faults []Fault
+}
+
+var _ Clock = &Faulty{}
+
+// NewFaulty creates a Faulty clock wrapping c with an initial script of
+// faults.
+func NewFaulty(c Clock, faults ...Fault) *Faulty {
+	f := &Faulty{clock: c, start: c.Now()}
+	f.Inject(faults...)
+	return f
+}
+
+// Inject adds faults to the script.
+func (f *Faulty) Inject(faults ...Fault) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.faults = append(f.faults, faults...)
+	sort.SliceStable(f.faults, func(i, j int) bool { return f.faults[i].At < f.faults[j].At })
+}
+
+// Elapsed returns the time elapsed on the wrapped clock since the Faulty
+// clock was created. It is convenient for injecting faults relative to now.
+func (f *Faulty) Elapsed() time.Duration {
+	return f.clock.Now().Sub(f.start)
+}
+
+func (f *Faulty) After(d time.Duration) <-chan time.Time {
+	return f.clock.After(d)
+}
+
+func (f *Faulty) AfterFunc(d time.Duration, fn func()) Timer {
+	return f.clock.AfterFunc(d, fn)
+}
+
+func (f *Faulty) NewTicker(d time.Duration) Ticker {
+	return f.clock.NewTicker(d)
+}
+
+func (f *Faulty) NewTimer(d time.Duration) Timer {
+	return f.clock.NewTimer(d)
+}
+
+// Now returns the wrapped clock's time with all faults applied.
+func (f *Faulty) Now() time.Time {
+	now := f.clock.Now()
+	elapsed := now.Sub(f.start)
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	for _, fault := range f.faults {
+		now = now.Add(fault.offset(elapsed))
+	}
+	return now.Round(0)
+}
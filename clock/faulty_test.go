@@ -0,0 +1,72 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaulty(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	c := NewFaulty(f,
+		Step(time.Minute, -time.Hour),
+		Freeze(2*time.Minute, 30*time.Second),
+	)
+	ctx := Onto(context.Background(), c)
+
+	at := func(elapsed time.Duration) time.Time {
+		f.Set(fakeEpoch.Add(elapsed))
+		return Now(ctx)
+	}
+
+	assert.Equal(t, fakeEpoch, at(0))
+	assert.Equal(t, fakeEpoch.Add(59*time.Second), at(59*time.Second))
+
+	// The clock steps backwards an hour.
+	stepped := at(time.Minute)
+	assert.Equal(t, fakeEpoch.Add(time.Minute-time.Hour), stepped)
+	assert.True(t, stepped.Before(at(59*time.Second)))
+
+	// The clock freezes for 30s, then resumes lagging behind.
+	frozen := fakeEpoch.Add(2*time.Minute - time.Hour)
+	assert.Equal(t, frozen, at(2*time.Minute))
+	assert.Equal(t, frozen, at(2*time.Minute+29*time.Second))
+	assert.Equal(t, frozen.Add(time.Second), at(2*time.Minute+31*time.Second))
+	assert.Equal(t, 2*time.Minute+31*time.Second, c.Elapsed())
+}
+
+func TestFaultyDrift(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	c := NewFaulty(f)
+	f.Advance(time.Hour)
+	c.Inject(Drift(c.Elapsed(), 0.001), Step(c.Elapsed()+time.Hour, time.Minute))
+
+	f.Advance(time.Hour)
+	assert.Equal(t, fakeEpoch.Add(2*time.Hour+time.Minute+3600*time.Millisecond), c.Now())
+}
+
+func TestFaultyTimersUnaffected(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	c := NewFaulty(f, Step(0, time.Hour))
+
+	ch := c.After(time.Minute)
+	timer := c.NewTimer(time.Minute)
+	ticker := c.NewTicker(time.Minute)
+	called := false
+	c.AfterFunc(time.Minute, func() { called = true })
+	f.Advance(time.Minute)
+
+	assert.Equal(t, fakeEpoch.Add(time.Minute), <-ch)
+	assert.Equal(t, fakeEpoch.Add(time.Minute), <-timer.C())
+	assert.Equal(t, fakeEpoch.Add(time.Minute), <-ticker.C())
+	assert.True(t, called)
+	ticker.Stop()
+}
@@ -0,0 +1,167 @@
+package clock
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Timestamp is a hybrid logical clock timestamp. Timestamps order events
+// causally across processes while staying close to physical time.
+type Timestamp struct {
+	// WallTime is the physical component in nanoseconds since the Unix epoch.
+	WallTime int64
+	// Logical orders events that share the same WallTime.
+	Logical uint32
+}
+
+// Compare returns -1, 0 or +1 depending on whether t is before, equal to or
+// after u.
+func (t Timestamp) Compare(u Timestamp) int {
+	switch {
+	case t.WallTime < u.WallTime:
+		return -1
+	case t.WallTime > u.WallTime:
+		return 1
+	case t.Logical < u.Logical:
+		return -1
+	case t.Logical > u.Logical:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Before reports whether t is before u.
+func (t Timestamp) Before(u Timestamp) bool {
+	return t.Compare(u) < 0
+}
+
+// IsZero reports whether t is the zero Timestamp.
+func (t Timestamp) IsZero() bool {
+	return t == Timestamp{}
+}
+
+// Time returns the physical component of t.
+func (t Timestamp) Time() time.Time {
+	return time.Unix(0, t.WallTime)
+}
+
+// timestampLen is the length of a formatted Timestamp: 16 hex digits of wall
+// time followed by 8 of logical time.
+const timestampLen = 24
+
+// String formats t as 24 lowercase hex digits. Formatted timestamps sort
+// lexically in the same order as Compare.
+func (t Timestamp) String() string {
+	return fmt.Sprintf("%016x%08x", uint64(t.WallTime), t.Logical)
+}
+
+// MarshalText implements encoding.TextMarshaler using String.
+func (t Timestamp) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseTimestamp.
+func (t *Timestamp) UnmarshalText(text []byte) error {
+	ts, err := ParseTimestamp(string(text))
+	if err != nil {
+		return err
+	}
+	*t = ts
+	return nil
+}
+
+// ParseTimestamp parses a Timestamp formatted by Timestamp.String.
+func ParseTimestamp(s string) (Timestamp, error) {
+	if len(s) != timestampLen {
+		return Timestamp{}, fmt.Errorf("clock: invalid timestamp %q: expected %d hex digits", s, timestampLen)
+	}
+	wall, err := strconv.ParseUint(s[:16], 16, 64)
+	if err != nil {
+		return Timestamp{}, fmt.Errorf("clock: invalid timestamp %q: %w", s, err)
+	}
+	logical, err := strconv.ParseUint(s[16:], 16, 32)
+	if err != nil {
+		return Timestamp{}, fmt.Errorf("clock: invalid timestamp %q: %w", s, err)
+	}
+	return Timestamp{WallTime: int64(wall), Logical: uint32(logical)}, nil
+}
+
+// HLC is a hybrid logical clock built on a Clock. Timestamps it issues never
+// go backwards, even if the underlying Clock does, and are always after any
+// remote timestamp passed to Update.
+type HLC struct {
+	clock Clock
+
+	mux  sync.Mutex
+	last Timestamp
+}
+
+// NewHLC creates an HLC that reads physical time from c.
+func NewHLC(c Clock) *HLC {
+	return &HLC{clock: c}
+}
+
+// Now returns a timestamp for a local or send event.
+func (h *HLC) Now() Timestamp {
+	pt := h.clock.Now().UnixNano()
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if pt > h.last.WallTime {
+		h.last = Timestamp{WallTime: pt}
+	} else {
+		h.last.Logical++
+	}
+	return h.last
+}
+
+// Update merges a timestamp received from a remote process and returns a
+// timestamp for the receive event.
+func (h *HLC) Update(remote Timestamp) Timestamp {
+	pt := h.clock.Now().UnixNano()
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	last := h.last
+	switch {
+	case pt > last.WallTime && pt > remote.WallTime:
+		h.last = Timestamp{WallTime: pt}
+	case last.WallTime == remote.WallTime:
+		h.last.Logical = last.Logical
+		if remote.Logical > last.Logical {
+			h.last.Logical = remote.Logical
+		}
+		h.last.Logical++
+	case last.WallTime > remote.WallTime:
+		h.last.Logical++
+	default:
+		h.last = Timestamp{WallTime: remote.WallTime, Logical: remote.Logical + 1}
+	}
+	return h.last
+}
+
+type hlcKey struct{}
+
+var defaultHLC = NewHLC(defaultClock{})
+
+// OntoHLC sets the context HLC.
+func OntoHLC(ctx context.Context, h *HLC) context.Context {
+	return context.WithValue(ctx, hlcKey{}, h)
+}
+
+// HLCFrom gets the HLC from the Context. If none has been set, it returns a
+// process-wide HLC when the context clock is the default one, and otherwise a
+// new HLC on the context clock. Timestamps are only guaranteed to be
+// monotonic across calls to the same HLC, so set one with OntoHLC when using
+// a mock clock.
+func HLCFrom(ctx context.Context) *HLC {
+	if h, ok := ctx.Value(hlcKey{}).(*HLC); ok && h != nil {
+		return h
+	}
+	if c := From(ctx); c != (defaultClock{}) {
+		return NewHLC(c)
+	}
+	return defaultHLC
+}
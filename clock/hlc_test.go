@@ -0,0 +1,108 @@
+package clock
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHLCNow(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	h := NewHLC(f)
+
+	t1 := h.Now()
+	assert.Equal(t, Timestamp{WallTime: fakeEpoch.UnixNano()}, t1)
+	t2 := h.Now()
+	assert.Equal(t, Timestamp{WallTime: fakeEpoch.UnixNano(), Logical: 1}, t2)
+	assert.True(t, t1.Before(t2))
+
+	// Timestamps never go backwards, even if the clock does.
+	f.Set(fakeEpoch.Add(-time.Hour))
+	t3 := h.Now()
+	assert.Equal(t, Timestamp{WallTime: fakeEpoch.UnixNano(), Logical: 2}, t3)
+
+	f.Set(fakeEpoch.Add(time.Second))
+	assert.Equal(t, Timestamp{WallTime: fakeEpoch.Add(time.Second).UnixNano()}, h.Now())
+	assert.Equal(t, fakeEpoch.Add(time.Second), h.Now().Time().UTC())
+}
+
+func TestHLCUpdate(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	h := NewHLC(f)
+	wall := fakeEpoch.UnixNano()
+
+	// A remote timestamp from the future wins.
+	remote := Timestamp{WallTime: wall + 10, Logical: 5}
+	assert.Equal(t, Timestamp{WallTime: wall + 10, Logical: 6}, h.Update(remote))
+
+	// Equal wall times take the larger logical time.
+	assert.Equal(t, Timestamp{WallTime: wall + 10, Logical: 10}, h.Update(Timestamp{WallTime: wall + 10, Logical: 9}))
+
+	// Older remote timestamps only bump the logical time.
+	assert.Equal(t, Timestamp{WallTime: wall + 10, Logical: 11}, h.Update(Timestamp{WallTime: wall}))
+
+	// Physical time catches up.
+	f.Advance(time.Second)
+	assert.Equal(t, Timestamp{WallTime: wall + int64(time.Second)}, h.Update(remote))
+}
+
+func TestTimestampFormat(t *testing.T) {
+	t.Parallel()
+
+	ts := Timestamp{WallTime: fakeEpoch.UnixNano(), Logical: 42}
+	assert.Equal(t, "15e59a35b98a00000000002a", ts.String())
+	parsed, err := ParseTimestamp(ts.String())
+	require.NoError(t, err)
+	assert.Equal(t, ts, parsed)
+
+	data, err := json.Marshal(map[string]Timestamp{"ts": ts})
+	require.NoError(t, err)
+	var decoded map[string]Timestamp
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, ts, decoded["ts"])
+
+	for _, s := range []string{"", "15e59a35b98a0000000002a", "x5e59a35b98a00000000002a", "15e59a35b98a0000x000002a"} {
+		_, err := ParseTimestamp(s)
+		assert.Error(t, err, s)
+	}
+	assert.Error(t, json.Unmarshal([]byte(`{"ts":"bad"}`), &decoded))
+
+	// Formatted timestamps sort like timestamps.
+	stamps := []Timestamp{{2, 0}, {1, 300}, {1, 2}, {0x100, 0}}
+	strs := make([]string, 0, len(stamps))
+	for _, s := range stamps {
+		strs = append(strs, s.String())
+	}
+	sort.Slice(stamps, func(i, j int) bool { return stamps[i].Before(stamps[j]) })
+	sort.Strings(strs)
+	for i, s := range stamps {
+		assert.Equal(t, s.String(), strs[i])
+	}
+	assert.Zero(t, stamps[0].Compare(stamps[0]))
+	assert.True(t, Timestamp{}.IsZero())
+}
+
+func TestHLCFrom(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	assert.Same(t, defaultHLC, HLCFrom(ctx))
+
+	h := NewHLC(NewFake(fakeEpoch))
+	assert.Same(t, h, HLCFrom(OntoHLC(ctx, h)))
+	assert.Same(t, defaultHLC, HLCFrom(OntoHLC(ctx, nil)))
+
+	// Without an HLC, a new one on the context clock is used.
+	fctx := Onto(ctx, NewFake(fakeEpoch))
+	assert.Equal(t, Timestamp{WallTime: fakeEpoch.UnixNano()}, HLCFrom(fctx).Now())
+	assert.NotSame(t, HLCFrom(fctx), HLCFrom(fctx))
+}
@@ -1,45 +1,263 @@
 package clock
 
-import "time"
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
 
-// Mock implements a mock Clock that reports a fixed sequence of times each time
-// it is queried.
+// Mock implements a scripted Clock for tests. Tests declare the sequence of
+// calls they expect with the Expect* methods, along with the values each call
+// returns. Calls that arrive out of order or that were not expected, and
+// expectations that are never met, are reported as errors on the testing.TB
+// rather than causing a panic.
 type Mock struct {
-	pending []time.Time
+	tb testing.TB
+
+	mux      sync.Mutex
+	expected []*Expectation
+	last     time.Time
 }
 
 var _ Clock = &Mock{}
 
-// NewMock creates a Mock Clock.
-func NewMock() *Mock {
-	return &Mock{}
+// Expectation is a call expected by a Mock. Expectations for After, AfterFunc,
+// NewTimer and NewTicker are fired explicitly with Fire or Return.
+type Expectation struct {
+	m      *Mock
+	method string
+	d      time.Duration
+	now    time.Time
+
+	called  bool
+	active  bool
+	c       chan time.Time
+	f       func()
+	pending []time.Time
+}
+
+// NewMock creates a Mock Clock that reports errors to tb. Expectations that
+// have not been met when tb's test finishes are reported as errors.
+func NewMock(tb testing.TB) *Mock {
+	m := &Mock{tb: tb}
+	tb.Cleanup(func() { m.AssertExpectations() })
+	return m
 }
 
+// AddTicks expects a call to Now for each of t, returning them in order.
 func (m *Mock) AddTicks(t ...time.Time) {
-	m.pending = append(m.pending, t...)
+	for _, t := range t {
+		m.ExpectNow().Return(t)
+	}
 }
 
-func (*Mock) After(d time.Duration) <-chan time.Time {
-	panic("not implemented")
+// ExpectNow expects a call to Now. The time returned is set with Return.
+func (m *Mock) ExpectNow() *Expectation {
+	return m.expect("Now", 0)
 }
 
-func (*Mock) AfterFunc(d time.Duration, f func()) Timer {
-	panic("not implemented")
+// ExpectAfter expects a call to After with duration d.
+func (m *Mock) ExpectAfter(d time.Duration) *Expectation {
+	return m.expect("After", d)
 }
 
-func (*Mock) NewTicker(d time.Duration) Ticker {
-	panic("not implemented")
+// ExpectAfterFunc expects a call to AfterFunc with duration d.
+func (m *Mock) ExpectAfterFunc(d time.Duration) *Expectation {
+	return m.expect("AfterFunc", d)
 }
 
-func (*Mock) NewTimer(d time.Duration) Timer {
-	panic("not implemented")
+// ExpectNewTicker expects a call to NewTicker with duration d.
+func (m *Mock) ExpectNewTicker(d time.Duration) *Expectation {
+	return m.expect("NewTicker", d)
 }
 
+// ExpectNewTimer expects a call to NewTimer with duration d.
+func (m *Mock) ExpectNewTimer(d time.Duration) *Expectation {
+	return m.expect("NewTimer", d)
+}
+
+// AssertExpectations reports an error for each expected call that has not
+// been made, and returns whether all expectations were met.
+func (m *Mock) AssertExpectations() bool {
+	m.mux.Lock()
+	missing := m.expected
+	m.mux.Unlock()
+	for _, e := range missing {
+		m.tb.Errorf("clock.Mock: missing call to %s", e)
+	}
+	return len(missing) == 0
+}
+
+func (m *Mock) After(d time.Duration) <-chan time.Time {
+	e, _ := m.call("After", d, nil)
+	return e.c
+}
+
+func (m *Mock) AfterFunc(d time.Duration, f func()) Timer {
+	e, _ := m.call("AfterFunc", d, f)
+	return &mockTimer{e}
+}
+
+func (m *Mock) NewTicker(d time.Duration) Ticker {
+	e, _ := m.call("NewTicker", d, nil)
+	return &mockTicker{e}
+}
+
+func (m *Mock) NewTimer(d time.Duration) Timer {
+	e, _ := m.call("NewTimer", d, nil)
+	return &mockTimer{e}
+}
+
+// Now returns the time of the next expected call to Now. If the call was not
+// expected, it returns the previously returned time.
 func (m *Mock) Now() time.Time {
-	if len(m.pending) == 0 {
-		panic("clock.Mock.Now: no ticks available")
+	e, ok := m.call("Now", 0, nil)
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	if ok {
+		m.last = e.now
+	}
+	return m.last
+}
+
+func (m *Mock) expect(method string, d time.Duration) *Expectation {
+	e := &Expectation{m: m, method: method, d: d}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.expected = append(m.expected, e)
+	return e
+}
+
+// call matches a call against the next expectation and reports whether it
+// was expected. Unexpected calls are reported and get a detached expectation
+// that never fires.
+func (m *Mock) call(method string, d time.Duration, f func()) (*Expectation, bool) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	got := &Expectation{m: m, method: method, d: d}
+	ok := false
+	switch {
+	case len(m.expected) == 0:
+		m.tb.Errorf("clock.Mock: unexpected call to %s", got)
+	case m.expected[0].method != method || m.expected[0].d != d:
+		m.tb.Errorf("clock.Mock: unexpected call to %s, expected %s", got, m.expected[0])
+	default:
+		got = m.expected[0]
+		m.expected = m.expected[1:]
+		ok = true
+	}
+	got.called = true
+	got.active = true
+	got.f = f
+	if f == nil {
+		got.c = make(chan time.Time, 1)
+	}
+	for _, t := range got.pending {
+		if f := got.fireLocked(t); f != nil {
+			go f()
+		}
+	}
+	got.pending = nil
+	return got, ok
+}
+
+// Return sets the time returned by an expected call to Now. For other calls
+// it is the same as Fire.
+func (e *Expectation) Return(t time.Time) {
+	e.m.mux.Lock()
+	if e.method == "Now" {
+		e.now = t
+		e.m.mux.Unlock()
+		return
+	}
+	e.m.mux.Unlock()
+	e.Fire(t)
+}
+
+// Fire sends t on the channel of an expected After, NewTimer or NewTicker
+// call, or calls the function passed to an expected AfterFunc call. If the
+// call has not been made yet, it fires as soon as it is made; functions passed
+// to AfterFunc then run in their own goroutine. Firing a stopped timer or
+// ticker, or a timer that has already fired, does nothing.
+func (e *Expectation) Fire(t time.Time) {
+	e.m.mux.Lock()
+	if e.method == "Now" {
+		e.m.mux.Unlock()
+		e.m.tb.Errorf("clock.Mock: cannot fire %s", e)
+		return
+	}
+	if !e.called {
+		e.pending = append(e.pending, t)
+		e.m.mux.Unlock()
+		return
+	}
+	f := e.fireLocked(t)
+	e.m.mux.Unlock()
+	if f != nil {
+		f()
+	}
+}
+
+// String describes the expected call.
+func (e *Expectation) String() string {
+	if e.method == "Now" {
+		return "Now()"
+	}
+	return fmt.Sprintf("%s(%v)", e.method, e.d)
+}
+
+func (e *Expectation) fireLocked(t time.Time) func() {
+	if !e.active {
+		return nil
+	}
+	if e.method != "NewTicker" {
+		e.active = false
+	}
+	if e.f != nil {
+		return e.f
+	}
+	select {
+	case e.c <- t:
+	default:
 	}
-	t := m.pending[0]
-	m.pending = m.pending[1:]
-	return t
+	return nil
+}
+
+type mockTimer struct {
+	e *Expectation
+}
+
+func (t *mockTimer) C() <-chan time.Time {
+	return t.e.c
+}
+
+func (t *mockTimer) Reset(time.Duration) bool {
+	t.e.m.mux.Lock()
+	defer t.e.m.mux.Unlock()
+	active := t.e.active
+	t.e.active = true
+	return active
+}
+
+func (t *mockTimer) Stop() bool {
+	t.e.m.mux.Lock()
+	defer t.e.m.mux.Unlock()
+	active := t.e.active
+	t.e.active = false
+	return active
+}
+
+type mockTicker struct {
+	e *Expectation
+}
+
+func (t *mockTicker) C() <-chan time.Time {
+	return t.e.c
+}
+
+func (t *mockTicker) Stop() {
+	t.e.m.mux.Lock()
+	defer t.e.m.mux.Unlock()
+	t.e.active = false
 }
@@ -2,18 +2,45 @@ package clock
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 )
 
+// fakeTB records errors and cleanups reported by a Mock.
+type fakeTB struct {
+	testing.TB
+
+	mux      sync.Mutex
+	errors   []string
+	cleanups []func()
+}
+
+func (tb *fakeTB) Errorf(format string, args ...interface{}) {
+	tb.mux.Lock()
+	defer tb.mux.Unlock()
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func (tb *fakeTB) Cleanup(f func()) {
+	tb.cleanups = append(tb.cleanups, f)
+}
+
+func (tb *fakeTB) Errors() []string {
+	tb.mux.Lock()
+	defer tb.mux.Unlock()
+	return tb.errors
+}
+
 func TestMock(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	now := Now(ctx)
-	m := NewMock()
+	m := NewMock(t)
 	m.AddTicks(
 		now.Add(0*time.Hour),
 		now.Add(1*time.Hour),
@@ -23,17 +50,100 @@ func TestMock(t *testing.T) {
 	assert.Equal(t, now.Add(0*time.Hour), Now(ctx))
 	assert.Equal(t, now.Add(1*time.Hour), Now(ctx))
 	assert.Equal(t, now.Add(2*time.Hour), Now(ctx))
-	assert.Panics(t, func() { Now(ctx) })
+	assert.True(t, m.AssertExpectations())
 }
 
-func TestMockNotImplemented(t *testing.T) {
+func TestMockScript(t *testing.T) {
 	t.Parallel()
 
-	ctx := context.Background()
-	ctx = Onto(ctx, NewMock())
+	m := NewMock(t)
+	ctx := Onto(context.Background(), m)
+
+	m.ExpectNow().Return(fakeEpoch)
+	m.ExpectAfter(time.Second).Return(fakeEpoch.Add(time.Second))
+	timerCall := m.ExpectNewTimer(time.Minute)
+	tickerCall := m.ExpectNewTicker(time.Hour)
+	funcCall := m.ExpectAfterFunc(time.Millisecond)
+
+	assert.Equal(t, fakeEpoch, Now(ctx))
+	assert.Equal(t, fakeEpoch.Add(time.Second), <-After(ctx, time.Second))
+
+	timer := NewTimer(ctx, time.Minute)
+	assert.Empty(t, timer.C())
+	timerCall.Fire(fakeEpoch.Add(time.Minute))
+	assert.Equal(t, fakeEpoch.Add(time.Minute), <-timer.C())
+	assert.False(t, timer.Stop())
+	timerCall.Fire(fakeEpoch)
+	assert.Empty(t, timer.C())
+	assert.False(t, timer.Reset(time.Minute))
+	assert.True(t, timer.Stop())
+
+	ticker := NewTicker(ctx, time.Hour)
+	tickerCall.Fire(fakeEpoch.Add(time.Hour))
+	assert.Equal(t, fakeEpoch.Add(time.Hour), <-ticker.C())
+	tickerCall.Fire(fakeEpoch.Add(2 * time.Hour))
+	assert.Equal(t, fakeEpoch.Add(2*time.Hour), <-ticker.C())
+	ticker.Stop()
+	tickerCall.Fire(fakeEpoch.Add(3 * time.Hour))
+	assert.Empty(t, ticker.C())
+
+	called := 0
+	AfterFunc(ctx, time.Millisecond, func() { called++ })
+	funcCall.Fire(fakeEpoch)
+	assert.Equal(t, 1, called)
+}
+
+func TestMockFireBeforeCall(t *testing.T) {
+	t.Parallel()
+
+	m := NewMock(t)
+	ctx := Onto(context.Background(), m)
+
+	done := make(chan struct{})
+	m.ExpectAfterFunc(time.Second).Fire(fakeEpoch)
+	AfterFunc(ctx, time.Second, func() { close(done) })
+	<-done
+}
+
+func TestMockUnexpected(t *testing.T) {
+	t.Parallel()
+
+	tb := &fakeTB{}
+	m := NewMock(tb)
+	ctx := Onto(context.Background(), m)
+
+	m.ExpectNow().Return(fakeEpoch)
+	m.ExpectAfter(time.Second)
+	m.ExpectNewTimer(time.Second)
+
+	// Calls out of order are reported, not consumed.
+	assert.NotNil(t, After(ctx, time.Minute))
+	assert.Equal(t, []string{"clock.Mock: unexpected call to After(1m0s), expected Now()"}, tb.Errors())
+	assert.Equal(t, fakeEpoch, Now(ctx))
+	After(ctx, time.Second)
+	assert.True(t, m.NewTimer(time.Second).Stop())
+
+	// Unexpected calls get inert timers and the last time returned by Now.
+	tb.errors = nil
+	assert.Equal(t, fakeEpoch, Now(ctx))
+	assert.NotNil(t, m.AfterFunc(time.Second, func() {}))
+	m.NewTicker(time.Second).Stop()
+	assert.Equal(t, []string{
+		"clock.Mock: unexpected call to Now()",
+		"clock.Mock: unexpected call to AfterFunc(1s)",
+		"clock.Mock: unexpected call to NewTicker(1s)",
+	}, tb.Errors())
 
-	assert.Panics(t, func() { After(ctx, time.Millisecond) })
-	assert.Panics(t, func() { AfterFunc(ctx, time.Millisecond, func() {}) })
-	assert.Panics(t, func() { NewTicker(ctx, time.Millisecond) })
-	assert.Panics(t, func() { NewTimer(ctx, time.Millisecond) })
+	// Unmet expectations are reported when the test finishes.
+	tb.errors = nil
+	m.ExpectNow().Fire(fakeEpoch)
+	m.ExpectNewTicker(time.Hour)
+	for _, f := range tb.cleanups {
+		f()
+	}
+	assert.Equal(t, []string{
+		"clock.Mock: cannot fire Now()",
+		"clock.Mock: missing call to Now()",
+		"clock.Mock: missing call to NewTicker(1h0m0s)",
+	}, tb.Errors())
 }
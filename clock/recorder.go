@@ -0,0 +1,100 @@
+package clock
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/anz-bank/pkg/internal/caller"
+)
+
+// Call describes a single query made on a Recorder.
+type Call struct {
+	// Method is the name of the Clock method called.
+	Method string
+	// Duration is the duration argument. It is 0 for Now.
+	Duration time.Duration
+	// Time is the time returned by Now. It is zero for other methods, as the
+	// Recorder does not query the wrapped Clock on their behalf.
+	Time time.Time
+	// File and Line locate the caller outside package clock.
+	File string
+	Line int
+}
+
+func (c Call) String() string {
+	if c.Method != "Now" {
+		return fmt.Sprintf("%s:%d: %s(%v)", filepath.Base(c.File), c.Line, c.Method, c.Duration)
+	}
+	return fmt.Sprintf("%s:%d: %s() at %v", filepath.Base(c.File), c.Line, c.Method, c.Time)
+}
+
+// Recorder is a Clock that records every time query and timer creation before
+// delegating to a wrapped Clock.
+type Recorder struct {
+	clock Clock
+
+	mux   sync.Mutex
+	calls []Call
+}
+
+var _ Clock = &Recorder{}
+
+// NewRecorder creates a Recorder wrapping c.
+func NewRecorder(c Clock) *Recorder {
+	return &Recorder{clock: c}
+}
+
+// Calls returns the calls recorded so far, oldest first.
+func (r *Recorder) Calls() []Call {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// Reset discards all recorded calls.
+func (r *Recorder) Reset() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.calls = nil
+}
+
+func (r *Recorder) After(d time.Duration) <-chan time.Time {
+	r.record("After", d)
+	return r.clock.After(d)
+}
+
+func (r *Recorder) AfterFunc(d time.Duration, f func()) Timer {
+	r.record("AfterFunc", d)
+	return r.clock.AfterFunc(d, f)
+}
+
+func (r *Recorder) NewTicker(d time.Duration) Ticker {
+	r.record("NewTicker", d)
+	return r.clock.NewTicker(d)
+}
+
+func (r *Recorder) NewTimer(d time.Duration) Timer {
+	r.record("NewTimer", d)
+	return r.clock.NewTimer(d)
+}
+
+func (r *Recorder) Now() time.Time {
+	now := r.clock.Now()
+	r.recordCall(Call{Method: "Now", Time: now})
+	return now
+}
+
+func (r *Recorder) record(method string, d time.Duration) {
+	r.recordCall(Call{Method: method, Duration: d})
+}
+
+func (r *Recorder) recordCall(c Call) {
+	c.File, c.Line = caller.Outside(packageDir)
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.calls = append(r.calls, c)
+}
+
+var packageDir = caller.Dir()
@@ -0,0 +1,58 @@
+package clock
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	r := NewRecorder(f)
+	ctx := Onto(context.Background(), r)
+
+	Now(ctx)
+	f.Advance(time.Second)
+	After(ctx, time.Minute)
+	AfterFunc(ctx, time.Hour, func() {})
+	NewTicker(ctx, time.Second).Stop()
+	NewTimer(ctx, 2*time.Second).Stop()
+	SleepContext(ctx, 0) //nolint:errcheck
+
+	calls := r.Calls()
+	if assert.Len(t, calls, 6) {
+		methods := []string{}
+		for _, c := range calls {
+			methods = append(methods, c.Method)
+			assert.Equal(t, "recorder_test.go", filepath.Base(c.File))
+		}
+		assert.Equal(t, []string{"Now", "After", "AfterFunc", "NewTicker", "NewTimer", "NewTimer"}, methods)
+		assert.Equal(t, fakeEpoch, calls[0].Time)
+		assert.Zero(t, calls[1].Time)
+		assert.Equal(t, time.Minute, calls[1].Duration)
+		assert.Equal(t, calls[0].Line+2, calls[1].Line)
+		assert.Regexp(t, `^recorder_test.go:\d+: After\(1m0s\)$`, calls[1].String())
+		assert.Regexp(t, `^recorder_test.go:\d+: Now\(\) at `, calls[0].String())
+	}
+
+	r.Reset()
+	assert.Empty(t, r.Calls())
+}
+
+func TestRecorderMakesNoExtraCalls(t *testing.T) {
+	t.Parallel()
+
+	m := NewMock(t)
+	m.ExpectAfter(time.Second).Return(fakeEpoch)
+	m.ExpectNow().Return(fakeEpoch)
+	r := NewRecorder(m)
+
+	assert.Equal(t, fakeEpoch, <-r.After(time.Second))
+	assert.Equal(t, fakeEpoch, r.Now())
+	assert.Len(t, r.Calls(), 2)
+}
@@ -0,0 +1,244 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Scaled is a Clock that runs at a multiple of real time. Now, timers and
+// tickers are all scaled consistently, so at a rate of 60 a one-hour timer
+// fires after one real minute. The rate can be changed and the clock paused
+// and resumed at any time; pending waiters are rescheduled accordingly.
+type Scaled struct {
+	mux     sync.Mutex
+	rate    float64
+	paused  bool
+	origin  time.Time // virtual time at the last rate change
+	realAt  time.Time // real time at the last rate change
+	waiters map[*scaledWaiter]struct{}
+}
+
+var _ Clock = &Scaled{}
+
+// NewScaled creates a Scaled Clock that starts at start and runs at rate
+// times real time. It panics if rate is not positive.
+func NewScaled(start time.Time, rate float64) *Scaled {
+	if rate <= 0 {
+		panic("clock.NewScaled: non-positive rate")
+	}
+	return &Scaled{
+		rate:    rate,
+		origin:  start,
+		realAt:  time.Now(),
+		waiters: map[*scaledWaiter]struct{}{},
+	}
+}
+
+// Rate returns the current rate.
+func (s *Scaled) Rate() float64 {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.rate
+}
+
+// SetRate changes the rate. It panics if rate is not positive.
+func (s *Scaled) SetRate(rate float64) {
+	if rate <= 0 {
+		panic("clock.Scaled.SetRate: non-positive rate")
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.rebase()
+	s.rate = rate
+	s.rescheduleAll()
+}
+
+// Pause stops the clock. Waiters do not fire while it is paused.
+func (s *Scaled) Pause() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.rebase()
+	s.paused = true
+	s.rescheduleAll()
+}
+
+// Resume restarts a paused clock from where it stopped.
+func (s *Scaled) Resume() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.rebase()
+	s.paused = false
+	s.rescheduleAll()
+}
+
+func (s *Scaled) After(d time.Duration) <-chan time.Time {
+	return s.NewTimer(d).C()
+}
+
+func (s *Scaled) AfterFunc(d time.Duration, f func()) Timer {
+	return s.newTimer(d, nil, f)
+}
+
+func (s *Scaled) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock.Scaled.NewTicker: non-positive interval")
+	}
+	w := &scaledWaiter{clock: s, ch: make(chan time.Time, 1), period: d}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	w.deadline = s.now().Add(d)
+	s.schedule(w)
+	return scaledTicker{w}
+}
+
+func (s *Scaled) NewTimer(d time.Duration) Timer {
+	return s.newTimer(d, make(chan time.Time, 1), nil)
+}
+
+// Now returns the current scaled time.
+func (s *Scaled) Now() time.Time {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.now()
+}
+
+func (s *Scaled) newTimer(d time.Duration, ch chan time.Time, f func()) Timer {
+	w := &scaledWaiter{clock: s, ch: ch, fn: f}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	w.deadline = s.now().Add(d)
+	s.schedule(w)
+	return scaledTimer{w}
+}
+
+// now returns the current scaled time. The caller must hold s.mux.
+func (s *Scaled) now() time.Time {
+	if s.paused {
+		return s.origin
+	}
+	return s.origin.Add(time.Duration(float64(time.Since(s.realAt)) * s.rate))
+}
+
+// rebase moves the origin to the current time so that the rate or pause state
+// can change. The caller must hold s.mux.
+func (s *Scaled) rebase() {
+	s.origin = s.now()
+	s.realAt = time.Now()
+}
+
+// schedule arms a real timer for w. The caller must hold s.mux.
+func (s *Scaled) schedule(w *scaledWaiter) {
+	s.waiters[w] = struct{}{}
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	if s.paused {
+		return
+	}
+	d := time.Duration(float64(w.deadline.Sub(s.now())) / s.rate)
+	w.timer = time.AfterFunc(d, w.fire)
+}
+
+// unschedule disarms w and reports whether it was pending. The caller must
+// hold s.mux.
+func (s *Scaled) unschedule(w *scaledWaiter) bool {
+	if _, has := s.waiters[w]; !has {
+		return false
+	}
+	delete(s.waiters, w)
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	return true
+}
+
+// rescheduleAll rearms every pending waiter. The caller must hold s.mux.
+func (s *Scaled) rescheduleAll() {
+	for w := range s.waiters {
+		s.schedule(w)
+	}
+}
+
+type scaledWaiter struct {
+	clock    *Scaled
+	deadline time.Time
+	period   time.Duration
+	ch       chan time.Time
+	fn       func()
+	timer    *time.Timer
+}
+
+func (w *scaledWaiter) fire() {
+	s := w.clock
+	s.mux.Lock()
+	if _, has := s.waiters[w]; !has || s.paused {
+		s.mux.Unlock()
+		return
+	}
+	now := s.now()
+	if now.Before(w.deadline) {
+		// The real timer fired early due to rounding.
+		s.schedule(w)
+		s.mux.Unlock()
+		return
+	}
+	w.timer = nil
+	if w.period > 0 {
+		for !w.deadline.After(now) {
+			w.deadline = w.deadline.Add(w.period)
+		}
+		s.schedule(w)
+	} else {
+		delete(s.waiters, w)
+	}
+	s.mux.Unlock()
+
+	if w.fn != nil {
+		w.fn()
+		return
+	}
+	select {
+	case w.ch <- now:
+	default:
+	}
+}
+
+type scaledTicker struct {
+	w *scaledWaiter
+}
+
+func (t scaledTicker) C() <-chan time.Time {
+	return t.w.ch
+}
+
+func (t scaledTicker) Stop() {
+	t.w.clock.mux.Lock()
+	defer t.w.clock.mux.Unlock()
+	t.w.clock.unschedule(t.w)
+}
+
+type scaledTimer struct {
+	w *scaledWaiter
+}
+
+func (t scaledTimer) C() <-chan time.Time {
+	return t.w.ch
+}
+
+func (t scaledTimer) Reset(d time.Duration) bool {
+	s := t.w.clock
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	active := s.unschedule(t.w)
+	t.w.deadline = s.now().Add(d)
+	s.schedule(t.w)
+	return active
+}
+
+func (t scaledTimer) Stop() bool {
+	t.w.clock.mux.Lock()
+	defer t.w.clock.mux.Unlock()
+	return t.w.clock.unschedule(t.w)
+}
@@ -0,0 +1,84 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Time-sensitive tests use generous rates so that they finish in a fraction
+// of a second while still allowing for scheduling jitter.
+
+func TestScaled(t *testing.T) {
+	t.Parallel()
+
+	s := NewScaled(fakeEpoch, 3600)
+	ctx := Onto(context.Background(), s)
+	assert.Equal(t, 3600.0, s.Rate())
+
+	realT0 := time.Now()
+	<-After(ctx, 100*time.Second)
+	assert.InDelta(t, float64(100*time.Second/3600), float64(time.Since(realT0)), float64(20*time.Millisecond))
+	now := Now(ctx)
+	assert.False(t, now.Before(fakeEpoch.Add(100*time.Second)))
+	assert.True(t, now.Before(fakeEpoch.Add(200*time.Second)), "%v", now)
+
+	assert.Panics(t, func() { NewScaled(fakeEpoch, 0) })
+	assert.Panics(t, func() { s.SetRate(-1) })
+	assert.Panics(t, func() { s.NewTicker(0) })
+}
+
+func TestScaledPause(t *testing.T) {
+	t.Parallel()
+
+	s := NewScaled(fakeEpoch, 3600)
+	s.Pause()
+	paused := s.Now()
+	ch := s.After(time.Second)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, paused, s.Now())
+	assert.Empty(t, ch)
+
+	s.Resume()
+	assert.False(t, (<-ch).Before(paused.Add(time.Second)))
+}
+
+func TestScaledSetRate(t *testing.T) {
+	t.Parallel()
+
+	s := NewScaled(fakeEpoch, 1)
+	timer := s.NewTimer(time.Hour)
+	s.SetRate(36000)
+	realT0 := time.Now()
+	<-timer.C()
+	assert.Less(t, time.Since(realT0), time.Second)
+	assert.False(t, timer.Stop())
+	assert.False(t, timer.Reset(time.Hour))
+	assert.True(t, timer.Stop())
+}
+
+func TestScaledTicker(t *testing.T) {
+	t.Parallel()
+
+	s := NewScaled(fakeEpoch, 36000)
+	ticker := s.NewTicker(time.Minute)
+	t1 := <-ticker.C()
+	t2 := <-ticker.C()
+	ticker.Stop()
+	assert.False(t, t1.Before(fakeEpoch.Add(time.Minute)))
+	assert.False(t, t2.Before(fakeEpoch.Add(2*time.Minute)))
+}
+
+func TestScaledAfterFunc(t *testing.T) {
+	t.Parallel()
+
+	s := NewScaled(fakeEpoch, 36000)
+	done := make(chan time.Time)
+	s.AfterFunc(time.Hour, func() { done <- s.Now() })
+	assert.False(t, (<-done).Before(fakeEpoch.Add(time.Hour)))
+
+	stopped := s.AfterFunc(time.Hour, func() { t.Error("stopped callback ran") })
+	assert.True(t, stopped.Stop())
+}
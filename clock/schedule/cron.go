@@ -0,0 +1,205 @@
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseCron parses a standard five-field cron expression (minute, hour, day
+// of month, month and day of week). Fields may use *, lists, ranges, steps
+// and three-letter month and day names. The descriptors @yearly, @annually,
+// @monthly, @weekly, @daily, @midnight and @hourly are supported, as is
+// "@every <duration>".
+//
+// The expression may be prefixed with CRON_TZ=<zone> or TZ=<zone> to
+// evaluate it in that time zone. Otherwise it is evaluated in the location of
+// the time passed to Next.
+func ParseCron(spec string) (Schedule, error) {
+	return ParseCronInLocation(spec, nil)
+}
+
+// ParseCronInLocation is like ParseCron, but evaluates the expression in loc
+// unless the expression specifies its own time zone.
+func ParseCronInLocation(spec string, loc *time.Location) (Schedule, error) {
+	expr := strings.TrimSpace(spec)
+	if strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=") {
+		i := strings.IndexAny(expr, " \t")
+		if i < 0 {
+			return nil, fmt.Errorf("schedule: cron expression %q has no fields", spec)
+		}
+		name := expr[strings.IndexByte(expr, '=')+1 : i]
+		var err error
+		if loc, err = time.LoadLocation(name); err != nil {
+			return nil, fmt.Errorf("schedule: cron expression %q: %w", spec, err)
+		}
+		expr = strings.TrimSpace(expr[i:])
+	}
+
+	if strings.HasPrefix(expr, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(expr[len("@every "):]))
+		if err != nil {
+			return nil, fmt.Errorf("schedule: cron expression %q: %w", spec, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("schedule: cron expression %q: non-positive interval", spec)
+		}
+		return every(d), nil
+	}
+	if d, has := descriptors[expr]; has {
+		expr = d
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("schedule: cron expression %q: expected %d fields, found %d",
+			spec, len(cronFields), len(fields))
+	}
+	c := &cron{loc: loc}
+	sets := []*uint64{&c.minute, &c.hour, &c.dom, &c.month, &c.dow}
+	for i, f := range fields {
+		set, err := cronFields[i].parse(f)
+		if err != nil {
+			return nil, fmt.Errorf("schedule: cron expression %q: %w", spec, err)
+		}
+		*sets[i] = set
+	}
+	// Sunday may be written as 0 or 7.
+	if c.dow&(1<<7) != 0 {
+		c.dow = c.dow&^(1<<7) | 1
+	}
+	c.domStar = fields[2] == "*" || strings.HasPrefix(fields[2], "*/")
+	c.dowStar = fields[4] == "*" || strings.HasPrefix(fields[4], "*/")
+	return c, nil
+}
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{
+		"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec",
+	}},
+	{name: "day of week", min: 0, max: 7, names: []string{
+		"sun", "mon", "tue", "wed", "thu", "fri", "sat",
+	}},
+}
+
+// parse returns a bit set of the values matched by a comma-separated list of
+// items.
+func (f cronField) parse(s string) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(s, ",") {
+		lo, hi, step := f.min, f.max, 1
+		rng := item
+		if i := strings.IndexByte(item, '/'); i >= 0 {
+			var err error
+			if step, err = strconv.Atoi(item[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field %q", f.name, item)
+			}
+			rng = item[:i]
+		}
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			i := strings.IndexByte(rng, '-')
+			var err error
+			if lo, err = f.value(rng[:i]); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(rng[i+1:]); err != nil {
+				return 0, err
+			}
+		default:
+			var err error
+			if lo, err = f.value(rng); err != nil {
+				return 0, err
+			}
+			if step == 1 {
+				hi = lo
+			}
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("invalid range in %s field %q", f.name, item)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if name != "" && strings.EqualFold(s, name) {
+			return i, nil
+		}
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q", f.name, s)
+	}
+	return v, nil
+}
+
+type cron struct {
+	loc                           *time.Location
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+// maxCronYears bounds the search for expressions that never match, such as
+// "0 0 30 2 *".
+const maxCronYears = 5
+
+func (c *cron) Next(t time.Time) time.Time {
+	orig := t.Location()
+	if c.loc != nil {
+		t = t.In(c.loc)
+	}
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + maxCronYears
+
+	for t.Year() <= limit {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t.In(orig)
+		}
+	}
+	return time.Time{}
+}
+
+func (c *cron) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
@@ -0,0 +1,97 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	t.Parallel()
+
+	// Wednesday.
+	from := time.Date(2020, time.January, 1, 10, 30, 15, 0, time.UTC)
+	cases := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2020, time.January, 1, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2020, time.January, 1, 10, 45, 0, 0, time.UTC)},
+		{"5 * * * *", time.Date(2020, time.January, 1, 11, 5, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", time.Date(2020, time.January, 1, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * MON", time.Date(2020, time.January, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2020, time.January, 5, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jun *", time.Date(2020, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2020, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 15 * 1", time.Date(2020, time.January, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2020, time.January, 15, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2020, time.January, 1, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2020, time.January, 2, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2020, time.January, 5, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2020, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", from.Add(90 * time.Second)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.spec, func(t *testing.T) {
+			t.Parallel()
+			s, err := ParseCron(c.spec)
+			require.NoError(t, err)
+			assert.Equal(t, c.next, s.Next(from))
+		})
+	}
+}
+
+func TestParseCronTimeZone(t *testing.T) {
+	t.Parallel()
+
+	melbourne, err := time.LoadLocation("Australia/Melbourne")
+	require.NoError(t, err)
+	from := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	expected := time.Date(2020, time.January, 1, 17, 0, 0, 0, melbourne)
+
+	s, err := ParseCron("CRON_TZ=Australia/Melbourne 0 17 * * *")
+	require.NoError(t, err)
+	next := s.Next(from)
+	assert.True(t, expected.Equal(next), "%v", next)
+	assert.Equal(t, time.UTC, next.Location())
+
+	s, err = ParseCronInLocation("0 17 * * *", melbourne)
+	require.NoError(t, err)
+	assert.True(t, expected.Equal(s.Next(from)))
+
+	// Skip the non-existent hour when daylight saving starts.
+	s, err = ParseCronInLocation("30 2 * * *", melbourne)
+	require.NoError(t, err)
+	dst := time.Date(2020, time.October, 4, 0, 0, 0, 0, melbourne)
+	assert.True(t, time.Date(2020, time.October, 5, 2, 30, 0, 0, melbourne).Equal(s.Next(dst)), "%v", s.Next(dst))
+}
+
+func TestParseCronErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"x * * * *",
+		"@every",
+		"@every -1s",
+		"TZ=Nowhere/Special * * * * *",
+		"CRON_TZ=UTC",
+	} {
+		_, err := ParseCron(spec)
+		assert.Error(t, err, spec)
+	}
+}
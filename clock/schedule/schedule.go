@@ -0,0 +1,29 @@
+// Package schedule runs jobs periodically on the context clock. Schedules may
+// be given as cron expressions or fixed intervals. Because all waiting goes
+// through clock.From(ctx), schedules can be tested with clock.Fake or
+// clock.TimeTravel.
+package schedule
+
+import "time"
+
+// Schedule determines when a job runs.
+type Schedule interface {
+	// Next returns the first activation time strictly after t, or the zero
+	// time if there is none.
+	Next(t time.Time) time.Time
+}
+
+// Every returns a Schedule that activates at fixed intervals. It panics if
+// the interval is not positive.
+func Every(interval time.Duration) Schedule {
+	if interval <= 0 {
+		panic("schedule.Every: non-positive interval")
+	}
+	return every(interval)
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
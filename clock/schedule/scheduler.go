@@ -0,0 +1,197 @@
+package schedule
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/anz-bank/pkg/clock"
+)
+
+// Job is a unit of work run by a Scheduler. The context passed to it is the
+// one the Scheduler was created with.
+type Job func(ctx context.Context)
+
+// OverlapPolicy determines what happens when a job is due to run while a
+// previous run of the same job has not finished.
+type OverlapPolicy int
+
+const (
+	// Skip drops the new run.
+	Skip OverlapPolicy = iota
+	// Queue runs the new run once the previous runs have finished.
+	Queue
+	// Allow runs the new run concurrently with the previous runs.
+	Allow
+)
+
+type jobOptions struct {
+	policy OverlapPolicy
+	jitter time.Duration
+}
+
+// Option is used to configure a job added to a Scheduler. The With* functions
+// should be used to obtain options for passing to Add.
+type Option func(*jobOptions)
+
+// WithOverlapPolicy returns an Option that sets what happens when runs of a
+// job overlap. The default is Skip.
+func WithOverlapPolicy(policy OverlapPolicy) Option {
+	return func(o *jobOptions) {
+		o.policy = policy
+	}
+}
+
+// WithJitter returns an Option that delays each run by a random duration in
+// [0, jitter). Jitter does not affect when subsequent runs are scheduled.
+func WithJitter(jitter time.Duration) Option {
+	return func(o *jobOptions) {
+		o.jitter = jitter
+	}
+}
+
+// Scheduler runs jobs according to their Schedules. All timing uses the
+// context clock of the context the Scheduler was created with.
+type Scheduler struct {
+	ctx   context.Context
+	loops sync.WaitGroup
+	runs  sync.WaitGroup
+
+	mux    sync.Mutex
+	stop   chan struct{}
+	closed bool
+}
+
+// New creates a Scheduler. Jobs stop being scheduled when ctx is done or Stop
+// is called.
+func New(ctx context.Context) *Scheduler {
+	return &Scheduler{ctx: ctx, stop: make(chan struct{})}
+}
+
+// Add schedules job to run according to sched until the Scheduler is
+// stopped.
+func (s *Scheduler) Add(sched Schedule, job Job, options ...Option) {
+	e := &entry{s: s, schedule: sched, job: job}
+	for _, option := range options {
+		option(&e.jobOptions)
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.closed {
+		return
+	}
+	s.loops.Add(1)
+	go e.loop()
+}
+
+// Stop stops scheduling new runs, discards queued runs and waits for running
+// jobs to finish. It returns ctx.Err() if ctx is done before then.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mux.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.stop)
+	}
+	s.mux.Unlock()
+	done := make(chan struct{})
+	go func() {
+		s.loops.Wait()
+		s.runs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) stopped() bool {
+	select {
+	case <-s.stop:
+		return true
+	case <-s.ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// wait blocks until t plus d or until the Scheduler stops, reporting whether
+// the time was reached.
+func (s *Scheduler) wait(t time.Time, d time.Duration) bool {
+	timer := clock.NewTimer(s.ctx, clock.Until(s.ctx, t)+d)
+	select {
+	case <-timer.C():
+		return true
+	case <-s.stop:
+	case <-s.ctx.Done():
+	}
+	timer.Stop()
+	return false
+}
+
+type entry struct {
+	jobOptions
+	s        *Scheduler
+	schedule Schedule
+	job      Job
+
+	mux     sync.Mutex
+	running bool
+	pending int
+}
+
+func (e *entry) loop() {
+	defer e.s.loops.Done()
+	now := clock.Now(e.s.ctx)
+	for next := e.schedule.Next(now); !next.IsZero(); {
+		var jitter time.Duration
+		if e.jitter > 0 {
+			jitter = time.Duration(rand.Int63n(int64(e.jitter))) //nolint:gosec
+		}
+		if !e.s.wait(next, jitter) {
+			return
+		}
+		e.trigger()
+
+		// Skip any activations missed while waiting.
+		now = clock.Now(e.s.ctx)
+		if next = e.schedule.Next(next); !next.IsZero() && next.Before(now) {
+			next = e.schedule.Next(now)
+		}
+	}
+}
+
+func (e *entry) trigger() {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	if e.running && e.policy != Allow {
+		if e.policy == Queue {
+			e.pending++
+		}
+		return
+	}
+	e.running = true
+	e.s.runs.Add(1)
+	go e.run()
+}
+
+func (e *entry) run() {
+	defer e.s.runs.Done()
+	for {
+		e.job(e.s.ctx)
+
+		e.mux.Lock()
+		if e.pending == 0 || e.s.stopped() {
+			e.pending = 0
+			e.running = false
+			e.mux.Unlock()
+			return
+		}
+		e.pending--
+		e.mux.Unlock()
+	}
+}
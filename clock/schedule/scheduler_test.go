@@ -0,0 +1,161 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anz-bank/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var epoch = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// tick advances f by d once the scheduler is waiting on it.
+func tick(t *testing.T, f *clock.Fake, d time.Duration) {
+	t.Helper()
+	require.NoError(t, f.WaitForWaiters(context.Background(), 1))
+	f.Advance(d)
+}
+
+func TestScheduler(t *testing.T) {
+	t.Parallel()
+
+	f := clock.NewFake(epoch)
+	ctx := clock.Onto(context.Background(), f)
+	s := New(ctx)
+
+	runs := make(chan time.Time)
+	s.Add(Every(time.Minute), func(ctx context.Context) { runs <- clock.Now(ctx) })
+	for i := 1; i <= 3; i++ {
+		tick(t, f, time.Minute)
+		assert.Equal(t, epoch.Add(time.Duration(i)*time.Minute), <-runs)
+	}
+	assert.NoError(t, s.Stop(context.Background()))
+
+	// Jobs added after stopping never run.
+	s.Add(Every(time.Minute), func(ctx context.Context) { t.Error("job ran after Stop") })
+	f.Advance(time.Hour)
+}
+
+func TestSchedulerCron(t *testing.T) {
+	t.Parallel()
+
+	f := clock.NewFake(epoch)
+	ctx := clock.Onto(context.Background(), f)
+	s := New(ctx)
+	defer s.Stop(context.Background()) //nolint:errcheck
+
+	sched, err := ParseCron("0 9 * * *")
+	require.NoError(t, err)
+	runs := make(chan time.Time)
+	s.Add(sched, func(ctx context.Context) { runs <- clock.Now(ctx) })
+	tick(t, f, 9*time.Hour)
+	assert.Equal(t, epoch.Add(9*time.Hour), <-runs)
+}
+
+func TestSchedulerJitter(t *testing.T) {
+	t.Parallel()
+
+	f := clock.NewFake(epoch)
+	ctx := clock.Onto(context.Background(), f)
+	s := New(ctx)
+	defer s.Stop(context.Background()) //nolint:errcheck
+
+	s.Add(Every(time.Hour), func(ctx context.Context) {}, WithJitter(time.Minute))
+	require.NoError(t, f.WaitForWaiters(ctx, 1))
+	deadline := f.Waiters()[0]
+	assert.False(t, deadline.Before(epoch.Add(time.Hour)))
+	assert.True(t, deadline.Before(epoch.Add(time.Hour+time.Minute)))
+}
+
+func TestSchedulerOverlap(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		policy   OverlapPolicy
+		expected int
+	}{
+		{Skip, 1},
+		{Queue, 3},
+		{Allow, 3},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run("", func(t *testing.T) {
+			t.Parallel()
+
+			f := clock.NewFake(epoch)
+			ctx := clock.Onto(context.Background(), f)
+			s := New(ctx)
+
+			started := make(chan struct{}, 3)
+			release := make(chan struct{})
+			s.Add(Every(time.Minute), func(context.Context) {
+				started <- struct{}{}
+				<-release
+			}, WithOverlapPolicy(c.policy))
+
+			tick(t, f, time.Minute)
+			<-started
+			tick(t, f, time.Minute)
+			tick(t, f, time.Minute)
+			require.NoError(t, f.WaitForWaiters(ctx, 1))
+			if c.policy == Allow {
+				<-started
+				<-started
+			}
+			close(release)
+			require.NoError(t, f.WaitForWaiters(ctx, 1))
+
+			// Queued runs follow once the first run finishes.
+			if c.policy == Queue {
+				<-started
+				<-started
+			}
+			assert.NoError(t, s.Stop(context.Background()))
+			assert.Empty(t, started)
+		})
+	}
+}
+
+func TestSchedulerStopTimeout(t *testing.T) {
+	t.Parallel()
+
+	f := clock.NewFake(epoch)
+	ctx := clock.Onto(context.Background(), f)
+	s := New(ctx)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	s.Add(Every(time.Minute), func(context.Context) {
+		close(started)
+		<-release
+	})
+	tick(t, f, time.Minute)
+	<-started
+
+	stopCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, s.Stop(stopCtx))
+	close(release)
+	assert.NoError(t, s.Stop(context.Background()))
+}
+
+func TestSchedulerTimeTravel(t *testing.T) {
+	t.Parallel()
+
+	tt := clock.NewTimeTravelStartingAt(epoch)
+	defer tt.Close()
+	ctx := clock.Onto(context.Background(), tt)
+	s := New(ctx)
+
+	runs := make(chan struct{}, 24)
+	s.Add(Every(time.Hour), func(context.Context) { runs <- struct{}{} })
+	for i := 0; i < 24; i++ {
+		<-runs
+	}
+	assert.NoError(t, s.Stop(context.Background()))
+	assert.False(t, clock.Now(ctx).Before(epoch.Add(24*time.Hour)))
+}
@@ -0,0 +1,103 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Stopwatch measures elapsed time on a Clock. Time only accumulates while the
+// stopwatch is running. Durations are computed from the Clock's Now, so they
+// use the monotonic clock reading when the Clock provides one.
+type Stopwatch struct {
+	clock Clock
+
+	mux     sync.Mutex
+	running bool
+	start   time.Time
+	elapsed time.Duration
+	lap     time.Duration
+}
+
+// NewStopwatch creates a stopped Stopwatch on the context clock.
+func NewStopwatch(ctx context.Context) *Stopwatch {
+	return &Stopwatch{clock: From(ctx)}
+}
+
+// StartStopwatch creates a running Stopwatch on the context clock.
+func StartStopwatch(ctx context.Context) *Stopwatch {
+	s := NewStopwatch(ctx)
+	s.Start()
+	return s
+}
+
+// Start starts or resumes the stopwatch. It does nothing if the stopwatch is
+// already running.
+func (s *Stopwatch) Start() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if !s.running {
+		s.running = true
+		s.start = s.clock.Now()
+	}
+}
+
+// Stop pauses the stopwatch and returns the total elapsed time.
+func (s *Stopwatch) Stop() time.Duration {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.running {
+		s.elapsed += s.clock.Now().Sub(s.start)
+		s.running = false
+	}
+	return s.elapsed
+}
+
+// Lap returns the elapsed time since the previous call to Lap, or since the
+// stopwatch was started or reset, and begins a new lap.
+func (s *Stopwatch) Lap() time.Duration {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	elapsed := s.elapsedLocked()
+	lap := elapsed - s.lap
+	s.lap = elapsed
+	return lap
+}
+
+// Elapsed returns the total time the stopwatch has been running.
+func (s *Stopwatch) Elapsed() time.Duration {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.elapsedLocked()
+}
+
+// Reset stops the stopwatch and clears the elapsed time.
+func (s *Stopwatch) Reset() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.running = false
+	s.elapsed = 0
+	s.lap = 0
+}
+
+// Running reports whether the stopwatch is running.
+func (s *Stopwatch) Running() bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.running
+}
+
+func (s *Stopwatch) elapsedLocked() time.Duration {
+	if s.running {
+		return s.elapsed + s.clock.Now().Sub(s.start)
+	}
+	return s.elapsed
+}
+
+// Measure calls f and returns how long it took according to the context
+// clock.
+func Measure(ctx context.Context, f func()) time.Duration {
+	s := StartStopwatch(ctx)
+	f()
+	return s.Stop()
+}
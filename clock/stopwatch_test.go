@@ -0,0 +1,49 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStopwatch(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	ctx := Onto(context.Background(), f)
+
+	s := NewStopwatch(ctx)
+	assert.False(t, s.Running())
+	f.Advance(time.Hour)
+	assert.Zero(t, s.Elapsed())
+
+	s.Start()
+	assert.True(t, s.Running())
+	f.Advance(time.Second)
+	assert.Equal(t, time.Second, s.Lap())
+	f.Advance(2 * time.Second)
+	assert.Equal(t, 3*time.Second, s.Stop())
+
+	// Stopped time is excluded from both the total and the current lap.
+	f.Advance(time.Hour)
+	s.Start()
+	f.Advance(time.Second)
+	assert.Equal(t, 3*time.Second, s.Lap())
+	assert.Equal(t, 4*time.Second, s.Elapsed())
+
+	s.Reset()
+	assert.False(t, s.Running())
+	assert.Zero(t, s.Elapsed())
+	assert.Zero(t, s.Lap())
+}
+
+func TestMeasure(t *testing.T) {
+	t.Parallel()
+
+	f := NewFake(fakeEpoch)
+	ctx := Onto(context.Background(), f)
+
+	assert.Equal(t, time.Minute, Measure(ctx, func() { f.Advance(time.Minute) }))
+}
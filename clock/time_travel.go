@@ -1,17 +1,29 @@
 package clock
 
 import (
+	"context"
 	"reflect"
+	"sort"
 	"time"
 )
 
 // TimeTravel is a mock Clock that offsets time by a given offset. Also, when
 // a call is made that involves a time delay, it travels to that time and
 // returns instantly.
+//
+// Timers and tickers travel when their channels are received from. AfterFunc
+// callbacks run, in deadline order, once the clock reaches their deadline.
+// That happens when real time catches up with them, when every pending
+// timer and ticker is due no earlier, or when a receiver travels past them.
+// In the last case the callbacks run just after the receive, concurrently
+// with the receiver. While a callback runs, no other waiters fire, so
+// callbacks must not block waiting on the same clock.
 type TimeTravel struct {
-	stop  chan struct{}
-	after chan afterRequest
-	now   chan (chan<- time.Time)
+	stop chan struct{}
+	add  chan *ttWaiter
+	ctl  chan ctlRequest
+	now  chan (chan<- time.Time)
+	wait chan waitersRequest
 }
 
 var _ Clock = &TimeTravel{}
@@ -19,9 +31,11 @@ var _ Clock = &TimeTravel{}
 // NewTimeTravel creates a TimeTravel Clock.
 func NewTimeTravel(initialOffset time.Duration) *TimeTravel {
 	t := &TimeTravel{
-		stop:  make(chan struct{}),
-		after: make(chan afterRequest),
-		now:   make(chan (chan<- time.Time)),
+		stop: make(chan struct{}),
+		add:  make(chan *ttWaiter),
+		ctl:  make(chan ctlRequest),
+		now:  make(chan (chan<- time.Time)),
+		wait: make(chan waitersRequest),
 	}
 	go t.run(initialOffset)
 	return t
@@ -32,48 +46,152 @@ func NewTimeTravelStartingAt(when time.Time) *TimeTravel {
 }
 
 func (t *TimeTravel) run(offset time.Duration) {
-	cases := []reflect.SelectCase{
-		{Dir: reflect.SelectSend, Chan: reflect.ValueOf(t.stop), Send: reflect.ValueOf(struct{}{})},
-		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(t.after)},
-		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(t.now)},
-	}
+	const (
+		stopCase = iota
+		addCase
+		ctlCase
+		nowCase
+		waitCase
+		doneCase
+		wakeCase
+		tail
+	)
 
 	nowPlus := func(d time.Duration) time.Time {
 		return time.Now().Add(offset + d)
 	}
+	travelTo := func(when time.Time) {
+		if d := time.Until(when); d > offset {
+			offset = d
+		}
+	}
+
+	// chans holds waiters that fire when their channel is received from. funcs
+	// holds AfterFunc waiters. Both are ordered by deadline.
+	var chans, funcs ttWaiters
+	var done chan struct{}
+	var blocked []waitersRequest
+
+	// answer replies to any WaitForWaiters requests that are now satisfied.
+	answer := func() {
+		pending := blocked[:0]
+		for _, req := range blocked {
+			if len(chans)+len(funcs) < req.n {
+				pending = append(pending, req)
+				continue
+			}
+			deadlines := make([]time.Time, 0, len(chans)+len(funcs))
+			for _, ws := range []ttWaiters{chans, funcs} {
+				for _, w := range ws {
+					deadlines = append(deadlines, w.deadline)
+				}
+			}
+			sort.Slice(deadlines, func(i, j int) bool { return deadlines[i].Before(deadlines[j]) })
+			req.reply <- deadlines
+		}
+		blocked = pending
+	}
 
 	for {
+		now := nowPlus(0)
+
+		if done == nil && len(funcs) > 0 {
+			f := funcs[0]
+			if !f.deadline.After(now) || len(chans) > 0 && !chans[0].deadline.Before(f.deadline) {
+				funcs.remove(f)
+				travelTo(f.deadline)
+				done = make(chan struct{})
+				go func(fn func(), done chan<- struct{}) {
+					defer close(done)
+					fn()
+				}(f.fn, done)
+				continue
+			}
+		}
+
+		cases := []reflect.SelectCase{
+			stopCase: {Dir: reflect.SelectSend, Chan: reflect.ValueOf(t.stop), Send: reflect.ValueOf(struct{}{})},
+			addCase:  {Dir: reflect.SelectRecv, Chan: reflect.ValueOf(t.add)},
+			ctlCase:  {Dir: reflect.SelectRecv, Chan: reflect.ValueOf(t.ctl)},
+			nowCase:  {Dir: reflect.SelectRecv, Chan: reflect.ValueOf(t.now)},
+			waitCase: {Dir: reflect.SelectRecv, Chan: reflect.ValueOf(t.wait)},
+			doneCase: {Dir: reflect.SelectRecv},
+			wakeCase: {Dir: reflect.SelectRecv},
+		}
+		var waiters ttWaiters
+		var wake *time.Timer
+		if done != nil {
+			cases[doneCase].Chan = reflect.ValueOf(done)
+		} else {
+			// Offer every channel, so that a timer nobody receives from
+			// doesn't hold back callbacks due before a later receiver.
+			waiters = chans
+			if len(funcs) > 0 {
+				wake = time.NewTimer(funcs[0].deadline.Sub(now))
+				cases[wakeCase].Chan = reflect.ValueOf(wake.C)
+			}
+			for _, w := range waiters {
+				send := w.deadline
+				if send.Before(now) {
+					send = now
+				}
+				cases = append(cases, reflect.SelectCase{
+					Dir:  reflect.SelectSend,
+					Chan: reflect.ValueOf(w.ch),
+					Send: reflect.ValueOf(send),
+				})
+			}
+		}
+
 		chosen, recv, _ := reflect.Select(cases)
+		if wake != nil {
+			wake.Stop()
+		}
 		switch chosen {
-		case 0:
+		case stopCase:
 			return
-		case 1:
-			req := recv.Interface().(afterRequest)
-			if req.d < 0 {
-				req.d = 0
+		case addCase:
+			w := recv.Interface().(*ttWaiter)
+			w.deadline = nowPlus(w.d)
+			if w.fn != nil {
+				funcs.insert(w)
+			} else {
+				chans.insert(w)
+			}
+			answer()
+		case ctlCase:
+			req := recv.Interface().(ctlRequest)
+			active := chans.remove(req.w) || funcs.remove(req.w)
+			if req.reset {
+				if req.d < 0 {
+					req.d = 0
+				}
+				req.w.deadline = nowPlus(req.d)
+				if req.w.fn != nil {
+					funcs.insert(req.w)
+				} else {
+					chans.insert(req.w)
+				}
 			}
-			cases = append(cases, reflect.SelectCase{
-				Dir:  reflect.SelectSend,
-				Chan: reflect.ValueOf(req.ch),
-				Send: reflect.ValueOf(nowPlus(req.d)),
-			})
-		case 2:
+			req.reply <- active
+			answer()
+		case nowCase:
 			ch := recv.Interface().(chan<- time.Time)
 			ch <- nowPlus(0)
+		case waitCase:
+			blocked = append(blocked, recv.Interface().(waitersRequest))
+			answer()
+		case doneCase:
+			done = nil
+		case wakeCase:
 		default:
-			const tail = 3
-			c := cases[chosen]
-			cases = append(cases[:chosen], cases[chosen+1:]...)
-
-			sent := c.Send.Interface().(time.Time)
-			offset = time.Until(sent)
-
-			waiters := cases[tail:]
-			for i, w := range waiters {
-				if w.Send.Interface().(time.Time).Before(sent) {
-					w.Send = reflect.ValueOf(nowPlus(0))
-					waiters[i] = w
-				}
+			w := waiters[chosen-tail]
+			sent := cases[chosen].Send.Interface().(time.Time)
+			travelTo(sent)
+			chans.remove(w)
+			if w.period > 0 {
+				w.deadline = sent.Add(w.period)
+				chans.insert(w)
 			}
 		}
 	}
@@ -88,21 +206,29 @@ func (t *TimeTravel) Close() {
 // real-world time API and avoid paradoxes, this function won't time-travel
 // backwards. Negative durations will be treated as 0.
 func (t *TimeTravel) After(d time.Duration) <-chan time.Time {
-	ch := make(chan time.Time)
-	t.after <- afterRequest{d: d, ch: ch}
-	return ch
+	return t.NewTimer(d).C()
 }
 
-func (*TimeTravel) AfterFunc(d time.Duration, f func()) Timer {
-	panic("not implemented")
+// AfterFunc calls f in its own goroutine once the clock reaches the deadline.
+func (t *TimeTravel) AfterFunc(d time.Duration, f func()) Timer {
+	return t.newTimer(d, nil, f)
 }
 
-func (*TimeTravel) NewTicker(d time.Duration) Ticker {
-	panic("not implemented")
+// NewTicker returns a Ticker that time-travels by the period each time its
+// channel is received from.
+func (t *TimeTravel) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock.TimeTravel.NewTicker: non-positive interval")
+	}
+	w := &ttWaiter{d: d, ch: make(chan time.Time), period: d}
+	t.add <- w
+	return ttTicker{t: t, w: w}
 }
 
-func (*TimeTravel) NewTimer(d time.Duration) Timer {
-	panic("not implemented")
+// NewTimer returns a Timer that time-travels to its deadline when its channel
+// is received from.
+func (t *TimeTravel) NewTimer(d time.Duration) Timer {
+	return t.newTimer(d, make(chan time.Time), nil)
 }
 
 // Now returns the current time adjusted by the time-travel offset.
@@ -112,7 +238,122 @@ func (t *TimeTravel) Now() time.Time {
 	return <-ch
 }
 
-type afterRequest struct {
-	d  time.Duration
-	ch chan<- time.Time
+// Waiters returns the deadlines of all pending timers, tickers and AfterFunc
+// callbacks in deadline order.
+func (t *TimeTravel) Waiters() []time.Time {
+	req := waitersRequest{reply: make(chan []time.Time, 1)}
+	t.wait <- req
+	return <-req.reply
+}
+
+// WaitForWaiters blocks until at least n timers, tickers or AfterFunc
+// callbacks are pending or ctx is done. Since waiters with a receiver travel
+// instantly, a waiter counts as soon as it is created, even if it fires
+// straight away.
+func (t *TimeTravel) WaitForWaiters(ctx context.Context, n int) error {
+	req := waitersRequest{n: n, reply: make(chan []time.Time, 1)}
+	select {
+	case t.wait <- req:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-req.reply:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (t *TimeTravel) newTimer(d time.Duration, ch chan time.Time, f func()) Timer {
+	if d < 0 {
+		d = 0
+	}
+	w := &ttWaiter{d: d, ch: ch, fn: f}
+	t.add <- w
+	return ttTimer{t: t, w: w}
+}
+
+func (t *TimeTravel) control(req ctlRequest) bool {
+	req.reply = make(chan bool)
+	t.ctl <- req
+	return <-req.reply
+}
+
+// ttWaiter is a pending timer, ticker or AfterFunc. Once sent to the event
+// loop, only the loop touches its deadline.
+type ttWaiter struct {
+	d        time.Duration
+	deadline time.Time
+	period   time.Duration
+	ch       chan time.Time
+	fn       func()
+}
+
+type ttWaiters []*ttWaiter
+
+// insert adds w after any waiters with the same deadline.
+func (ws *ttWaiters) insert(w *ttWaiter) {
+	s := *ws
+	i := sort.Search(len(s), func(i int) bool {
+		return s[i].deadline.After(w.deadline)
+	})
+	s = append(s, nil)
+	copy(s[i+1:], s[i:])
+	s[i] = w
+	*ws = s
+}
+
+// remove removes w and reports whether it was present.
+func (ws *ttWaiters) remove(w *ttWaiter) bool {
+	s := *ws
+	for i, v := range s {
+		if v == w {
+			*ws = append(s[:i], s[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type ctlRequest struct {
+	w     *ttWaiter
+	reset bool
+	d     time.Duration
+	reply chan bool
+}
+
+type waitersRequest struct {
+	n     int
+	reply chan []time.Time
+}
+
+type ttTicker struct {
+	t *TimeTravel
+	w *ttWaiter
+}
+
+func (t ttTicker) C() <-chan time.Time {
+	return t.w.ch
+}
+
+func (t ttTicker) Stop() {
+	t.t.control(ctlRequest{w: t.w})
+}
+
+type ttTimer struct {
+	t *TimeTravel
+	w *ttWaiter
+}
+
+func (t ttTimer) C() <-chan time.Time {
+	return t.w.ch
+}
+
+func (t ttTimer) Reset(d time.Duration) bool {
+	return t.t.control(ctlRequest{w: t.w, reset: true, d: d})
+}
+
+func (t ttTimer) Stop() bool {
+	return t.t.control(ctlRequest{w: t.w})
 }
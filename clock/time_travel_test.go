@@ -109,12 +109,133 @@ func TestTimeTravelMultipleAfters(t *testing.T) {
 	}
 }
 
-func TestTimeTravelNotImplemented(t *testing.T) {
+func TestTimeTravelTimer(t *testing.T) {
 	t.Parallel()
 
-	ctx := Onto(context.Background(), NewTimeTravel(0))
+	tt := NewTimeTravel(0)
+	defer tt.Close()
+	ctx := Onto(context.Background(), tt)
+	t0 := Now(ctx)
+
+	timer := NewTimer(ctx, time.Hour)
+	assertWithinOneSecond(t, t0.Add(time.Hour), <-timer.C())
+	assert.False(t, timer.Stop())
+
+	assert.False(t, timer.Reset(time.Hour))
+	assert.True(t, timer.Reset(2*time.Hour))
+	assertWithinOneSecond(t, t0.Add(3*time.Hour), <-timer.C())
+
+	assert.False(t, timer.Reset(time.Hour))
+	assert.True(t, timer.Stop())
+	assertWithinOneSecond(t, t0.Add(3*time.Hour), Now(ctx))
+}
+
+func TestTimeTravelTicker(t *testing.T) {
+	t.Parallel()
+
+	tt := NewTimeTravel(0)
+	defer tt.Close()
+	ctx := Onto(context.Background(), tt)
+	t0 := Now(ctx)
+
+	ticker := NewTicker(ctx, time.Hour)
+	for i := 1; i <= 3; i++ {
+		assertWithinOneSecond(t, t0.Add(time.Duration(i)*time.Hour), <-ticker.C(), "tick %d", i)
+	}
+	ticker.Stop()
+	assertWithinOneSecond(t, t0.Add(3*time.Hour), Now(ctx))
+
+	assert.Panics(t, func() { NewTicker(ctx, 0) })
+}
+
+func TestTimeTravelAfterFunc(t *testing.T) {
+	t.Parallel()
+
+	tt := NewTimeTravel(0)
+	defer tt.Close()
+	ctx := Onto(context.Background(), tt)
+	t0 := Now(ctx)
+
+	// Callbacks fire in deadline order before a later waiter is reached.
+	fired := make(chan time.Duration, 2)
+	AfterFunc(ctx, 2*time.Hour, func() { fired <- 2 * time.Hour })
+	AfterFunc(ctx, time.Hour, func() { fired <- time.Hour })
+	stopped := AfterFunc(ctx, 90*time.Minute, func() { fired <- 0 })
+	assert.Nil(t, stopped.C())
+	assert.True(t, stopped.Stop())
+
+	assertWithinOneSecond(t, t0.Add(3*time.Hour), <-After(ctx, 3*time.Hour))
+	assert.Equal(t, time.Hour, <-fired)
+	assert.Equal(t, 2*time.Hour, <-fired)
+
+	// Callbacks due in real time fire without a waiter.
+	done := make(chan struct{})
+	AfterFunc(ctx, 0, func() { close(done) })
+	<-done
+}
+
+func TestTimeTravelAfterFuncBeforeEarlierWaiter(t *testing.T) {
+	t.Parallel()
+
+	tt := NewTimeTravel(0)
+	defer tt.Close()
+	ctx := Onto(context.Background(), tt)
+	t0 := Now(ctx)
+
+	fired := make(chan struct{})
+	timer := AfterFunc(ctx, 2*time.Hour, func() { close(fired) })
+	assertWithinOneSecond(t, t0.Add(time.Hour), <-After(ctx, time.Hour))
+	select {
+	case <-fired:
+		t.Fatal("callback fired early")
+	default:
+	}
+	assert.True(t, timer.Stop())
+}
+
+func TestTimeTravelAbandonedAfter(t *testing.T) {
+	t.Parallel()
+
+	tt := NewTimeTravel(0)
+	defer tt.Close()
+	ctx := Onto(context.Background(), tt)
+
+	// An earlier timer nobody receives from, such as one that lost a select
+	// to another case, mustn't hold back a deadline that a later receiver
+	// travels past.
+	After(ctx, time.Hour)
+
+	tctx, cancel := WithTimeout(ctx, 2*time.Hour)
+	defer cancel()
+	go func() { <-After(ctx, 3*time.Hour) }()
+
+	select {
+	case <-tctx.Done():
+		assert.Equal(t, context.DeadlineExceeded, tctx.Err())
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadline didn't fire")
+	}
+}
+
+func TestTimeTravelWaitForWaiters(t *testing.T) {
+	t.Parallel()
+
+	tt := NewTimeTravel(0)
+	defer tt.Close()
+	ctx := Onto(context.Background(), tt)
+	t0 := Now(ctx)
+
+	timer := NewTimer(ctx, time.Hour)
+	go func() { AfterFunc(ctx, 2*time.Hour, func() {}) }()
+	assert.NoError(t, tt.WaitForWaiters(ctx, 2))
+	waiters := tt.Waiters()
+	if assert.Len(t, waiters, 2) {
+		assertWithinOneSecond(t, t0.Add(time.Hour), waiters[0])
+		assertWithinOneSecond(t, t0.Add(2*time.Hour), waiters[1])
+	}
+	assert.True(t, timer.Stop())
 
-	assert.Panics(t, func() { AfterFunc(ctx, time.Millisecond, func() {}) })
-	assert.Panics(t, func() { NewTicker(ctx, time.Millisecond) })
-	assert.Panics(t, func() { NewTimer(ctx, time.Millisecond) })
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Equal(t, context.Canceled, tt.WaitForWaiters(cctx, 3))
 }
@@ -0,0 +1,174 @@
+package env
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/anz-bank/pkg/internal/caller"
+)
+
+// Access describes a single variable lookup made through an Auditor.
+type Access struct {
+	Key string
+	// Present reports whether the variable was set.
+	Present bool
+	// File and Line locate the caller outside package env.
+	File string
+	Line int
+}
+
+func (a Access) String() string {
+	return fmt.Sprintf("%s:%d: %s (present: %t)", filepath.Base(a.File), a.Line, a.Key, a.Present)
+}
+
+// Usage summarises the lookups of one variable.
+type Usage struct {
+	Key string `json:"key"`
+	// Present reports whether the variable was set when last read.
+	Present bool `json:"present"`
+	Reads   int  `json:"reads"`
+	// Sites lists the distinct call sites as file:line, in order of first
+	// use.
+	Sites []string `json:"sites"`
+}
+
+// Auditor is an Env that records every variable lookup, including those made
+// by Getenv and the typed accessors, before delegating to a wrapped Env.
+type Auditor struct {
+	env Env
+
+	mux      sync.Mutex
+	accesses []Access
+}
+
+var _ Env = &Auditor{}
+
+// NewAuditor creates an Auditor wrapping e.
+func NewAuditor(e Env) *Auditor {
+	return &Auditor{env: e}
+}
+
+// Accesses returns the lookups recorded so far, oldest first.
+func (a *Auditor) Accesses() []Access {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	return append([]Access(nil), a.accesses...)
+}
+
+// Reset discards all recorded lookups.
+func (a *Auditor) Reset() {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	a.accesses = nil
+}
+
+// Report summarises the recorded lookups by variable, sorted by key.
+func (a *Auditor) Report() []Usage {
+	byKey := map[string]*Usage{}
+	for _, access := range a.Accesses() {
+		u := byKey[access.Key]
+		if u == nil {
+			u = &Usage{Key: access.Key}
+			byKey[access.Key] = u
+		}
+		u.Present = access.Present
+		u.Reads++
+		site := fmt.Sprintf("%s:%d", filepath.Base(access.File), access.Line)
+		if !contains(u.Sites, site) {
+			u.Sites = append(u.Sites, site)
+		}
+	}
+	result := make([]Usage, 0, len(byKey))
+	for _, u := range byKey {
+		result = append(result, *u)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// Unused returns the variables set in the wrapped Env that have not been
+// looked up, sorted by key.
+func (a *Auditor) Unused() []string {
+	read := map[string]bool{}
+	for _, access := range a.Accesses() {
+		read[access.Key] = true
+	}
+	var result []string
+	for _, kv := range a.env.Environ() {
+		if key, _, _ := strings.Cut(kv, "="); !read[key] {
+			result = append(result, key)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// WriteJSON writes the Report to w as a JSON array.
+func (a *Auditor) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a.Report())
+}
+
+// WriteMarkdown writes the Report to w as a markdown table.
+func (a *Auditor) WriteMarkdown(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("| Variable | Present | Reads | Call sites |\n")
+	sb.WriteString("| --- | --- | --- | --- |\n")
+	for _, u := range a.Report() {
+		present := "no"
+		if u.Present {
+			present = "yes"
+		}
+		fmt.Fprintf(&sb, "| `%s` | %s | %d | %s |\n", u.Key, present, u.Reads, strings.Join(u.Sites, ", "))
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (a *Auditor) Clearenv() {
+	a.env.Clearenv()
+}
+
+func (a *Auditor) Environ() []string {
+	return a.env.Environ()
+}
+
+func (a *Auditor) LookupEnv(key string) (string, bool) {
+	value, has := a.env.LookupEnv(key)
+	access := Access{Key: key, Present: has}
+	access.File, access.Line = caller.Outside(packageDir)
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	a.accesses = append(a.accesses, access)
+	return value, has
+}
+
+func (a *Auditor) Setenv(key, value string) error {
+	return a.env.Setenv(key, value)
+}
+
+func (a *Auditor) Unsetenv(key string) error {
+	return a.env.Unsetenv(key)
+}
+
+// IsSecret reports whether key is secret in the wrapped Env.
+func (a *Auditor) IsSecret(key string) bool {
+	return isSecret(a.env, key)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+var packageDir = caller.Dir()
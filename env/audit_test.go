@@ -0,0 +1,55 @@
+package env
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditor(t *testing.T) {
+	t.Parallel()
+
+	a := NewAuditor(NewMap(map[string]string{"PORT": "8080", "HOST": "localhost", "UNUSED": "x"}))
+	ctx := Onto(context.Background(), a)
+
+	readPort := func() { _, _ = Int(ctx, "PORT", 80) }
+	readPort()
+	readPort()
+	assert.Equal(t, "localhost", Getenv(ctx, "HOST"))
+	_, has := LookupEnv(ctx, "MISSING")
+	assert.False(t, has)
+
+	accesses := a.Accesses()
+	require.Len(t, accesses, 4)
+	assert.Equal(t, Access{Key: "PORT", Present: true, File: accesses[0].File, Line: accesses[0].Line}, accesses[0])
+	assert.Regexp(t, `^audit_test.go:\d+: PORT \(present: true\)$`, accesses[0].String())
+	assert.Equal(t, accesses[0], accesses[1])
+	assert.Equal(t, accesses[0].Line+3, accesses[2].Line)
+
+	report := a.Report()
+	require.Len(t, report, 3)
+	assert.Equal(t, "HOST", report[0].Key)
+	assert.Equal(t, Usage{Key: "MISSING", Reads: 1, Sites: report[1].Sites}, report[1])
+	assert.Equal(t, 2, report[2].Reads)
+	assert.Len(t, report[2].Sites, 1)
+	assert.Equal(t, []string{"UNUSED"}, a.Unused())
+
+	var buf bytes.Buffer
+	require.NoError(t, a.WriteJSON(&buf))
+	var decoded []Usage
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, report, decoded)
+
+	buf.Reset()
+	require.NoError(t, a.WriteMarkdown(&buf))
+	assert.Contains(t, buf.String(), "| Variable | Present | Reads | Call sites |\n| --- | --- | --- | --- |\n| `HOST` | yes | 1 | audit_test.go:")
+	assert.Contains(t, buf.String(), "| `MISSING` | no | 1 |")
+
+	a.Reset()
+	assert.Empty(t, a.Accesses())
+	assert.Equal(t, []string{"HOST", "PORT", "UNUSED"}, a.Unused())
+}
@@ -0,0 +1,235 @@
+package env
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Decoder is implemented by types that decode themselves from the value of
+// an environment variable. Bind uses it in preference to the built-in
+// decoding and to encoding.TextUnmarshaler.
+type Decoder interface {
+	DecodeEnv(value string) error
+}
+
+// BindError aggregates the errors found by Bind or Schema.Validate, one per
+// variable.
+type BindError struct {
+	Errors []error
+}
+
+func (e *BindError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (e *BindError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is reports whether any of the errors matches target. It lets errors.Is see
+// through a BindError before Go 1.20, which does not use Unwrap() []error.
+func (e *BindError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first of the errors that matches target, as for Is.
+func (e *BindError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	decoderType         = reflect.TypeOf((*Decoder)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	urlType             = reflect.TypeOf(url.URL{})
+)
+
+// Bind populates the struct pointed to by v from the context Env. Fields are
+// bound according to their tags:
+//
+//	env:"NAME"      the variable to read; fields without it are skipped
+//	default:"..."   the value to use if the variable is not set
+//	required:"true" report an error if the variable is not set
+//	sep:","         the separator between slice or map entries
+//	kvsep:"="       the separator between map keys and values
+//	prefix:"DB_"    on a struct field, prepended to its fields' names
+//
+// Fields of struct type are bound recursively unless they implement Decoder
+// or encoding.TextUnmarshaler. Fields whose variable is not set and has no
+// default are left unchanged. All errors are collected and returned together
+// as a *BindError whose entries are *VarErrors.
+func Bind(ctx context.Context, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("env: Bind requires a non-nil pointer to a struct, got %T", v)
+	}
+	var errs []error
+	bindStruct(From(ctx), rv.Elem(), "", &errs)
+	if len(errs) > 0 {
+		return &BindError{Errors: errs}
+	}
+	return nil
+}
+
+func bindStruct(env Env, v reflect.Value, prefix string, errs *[]error) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		fv := v.Field(i)
+		name, tagged := field.Tag.Lookup("env")
+		if name == "-" {
+			continue
+		}
+		if !tagged || !field.IsExported() {
+			if field.Type.Kind() == reflect.Struct && !isDecodable(fv) {
+				bindStruct(env, fv, prefix+field.Tag.Get("prefix"), errs)
+			}
+			continue
+		}
+		key := prefix + name
+		value, has := env.LookupEnv(key)
+		if !has {
+			value, has = field.Tag.Lookup("default")
+		}
+		if !has {
+			if field.Tag.Get("required") == "true" {
+				*errs = append(*errs, &VarError{Key: key, Err: ErrNotSet})
+			}
+			continue
+		}
+		if err := decode(fv, value, field.Tag); err != nil {
+			*errs = append(*errs, &VarError{Key: key, Err: err})
+		}
+	}
+}
+
+func isDecodable(v reflect.Value) bool {
+	t := v.Addr().Type()
+	return t.Implements(decoderType) || t.Implements(textUnmarshalerType) || v.Type() == urlType
+}
+
+func decode(v reflect.Value, s string, tag reflect.StructTag) error {
+	if v.Kind() == reflect.Ptr {
+		p := reflect.New(v.Type().Elem())
+		if err := decode(p.Elem(), s, tag); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+	if v.CanAddr() {
+		switch d := v.Addr().Interface().(type) {
+		case Decoder:
+			return d.DecodeEnv(s)
+		case encoding.TextUnmarshaler:
+			return d.UnmarshalText([]byte(s))
+		}
+	}
+
+	switch v.Type() {
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	case urlType:
+		u, err := url.Parse(s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(*u))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		parts, _ := parseStrings(tagOr(tag, "sep", ","))(s)
+		slice := reflect.MakeSlice(v.Type(), len(parts), len(parts))
+		for i, part := range parts {
+			if err := decode(slice.Index(i), part, tag); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+	case reflect.Map:
+		entries, err := parseStringMap(tagOr(tag, "sep", ","), tagOr(tag, "kvsep", "="))(s)
+		if err != nil {
+			return err
+		}
+		m := reflect.MakeMapWithSize(v.Type(), len(entries))
+		for k, e := range entries {
+			key := reflect.New(v.Type().Key()).Elem()
+			if err := decode(key, k, tag); err != nil {
+				return err
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := decode(elem, e, tag); err != nil {
+				return err
+			}
+			m.SetMapIndex(key, elem)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func tagOr(tag reflect.StructTag, key, def string) string {
+	if value, has := tag.Lookup(key); has {
+		return value
+	}
+	return def
+}
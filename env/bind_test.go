@@ -0,0 +1,132 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type level int
+
+func (l *level) DecodeEnv(value string) error {
+	switch strings.ToLower(value) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	default:
+		return errors.New("unknown level")
+	}
+	return nil
+}
+
+type dbConfig struct {
+	Host string        `env:"HOST" default:"localhost"`
+	Port uint16        `env:"PORT" default:"5432"`
+	TTL  time.Duration `env:"TTL"`
+}
+
+type config struct {
+	Name     string            `env:"NAME" required:"true"`
+	Debug    bool              `env:"DEBUG"`
+	Ratio    float64           `env:"RATIO" default:"0.5"`
+	Level    level             `env:"LEVEL" default:"info"`
+	Hosts    []string          `env:"HOSTS" sep:";"`
+	Ports    []int             `env:"PORTS"`
+	Labels   map[string]string `env:"LABELS" kvsep:":"`
+	Endpoint *url.URL          `env:"ENDPOINT"`
+	IP       net.IP            `env:"IP"`
+	Retries  *int              `env:"RETRIES"`
+	Primary  dbConfig          `prefix:"DB_"`
+	Replica  dbConfig          `prefix:"REPLICA_"`
+	Ignored  string            `env:"-"`
+	Kept     string
+	dbConfig
+}
+
+func TestBind(t *testing.T) {
+	t.Parallel()
+
+	ctx := Onto(context.Background(), NewMap(map[string]string{
+		"NAME":         "svc",
+		"DEBUG":        "true",
+		"LEVEL":        "debug",
+		"HOSTS":        "a; b",
+		"PORTS":        "80,443",
+		"LABELS":       "team:core,tier:1",
+		"ENDPOINT":     "https://example.com",
+		"IP":           "10.0.0.1",
+		"RETRIES":      "3",
+		"DB_HOST":      "db",
+		"DB_TTL":       "1m",
+		"REPLICA_PORT": "6543",
+		"HOST":         "embedded",
+		"Ignored":      "x",
+	}))
+
+	cfg := config{Kept: "kept", Level: 5}
+	require.NoError(t, Bind(ctx, &cfg))
+	retries := 3
+	assert.Equal(t, config{
+		Name:     "svc",
+		Debug:    true,
+		Ratio:    0.5,
+		Level:    0,
+		Hosts:    []string{"a", "b"},
+		Ports:    []int{80, 443},
+		Labels:   map[string]string{"team": "core", "tier": "1"},
+		Endpoint: &url.URL{Scheme: "https", Host: "example.com"},
+		IP:       net.ParseIP("10.0.0.1"),
+		Retries:  &retries,
+		Primary:  dbConfig{Host: "db", Port: 5432, TTL: time.Minute},
+		Replica:  dbConfig{Host: "localhost", Port: 6543},
+		Kept:     "kept",
+		dbConfig: dbConfig{Host: "embedded", Port: 5432},
+	}, cfg)
+}
+
+func TestBindErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := Onto(context.Background(), NewMap(map[string]string{
+		"LEVEL":   "loud",
+		"PORTS":   "80,http",
+		"DB_PORT": "70000",
+	}))
+
+	var cfg config
+	err := Bind(ctx, &cfg)
+	var bindErr *BindError
+	require.ErrorAs(t, err, &bindErr)
+	keys := []string{}
+	for _, err := range bindErr.Errors {
+		var varErr *VarError
+		require.ErrorAs(t, err, &varErr)
+		keys = append(keys, varErr.Key)
+	}
+	assert.Equal(t, []string{"NAME", "LEVEL", "PORTS", "DB_PORT"}, keys)
+	assert.ErrorIs(t, bindErr.Errors[0], ErrNotSet)
+	assert.True(t, bindErr.Is(ErrNotSet))
+	assert.False(t, bindErr.Is(ErrReadOnly))
+	var varErr *VarError
+	if assert.True(t, bindErr.As(&varErr)) {
+		assert.Equal(t, "NAME", varErr.Key)
+	}
+	assert.Contains(t, err.Error(), "env: NAME: not set; env: LEVEL: unknown level; ")
+
+	assert.Error(t, Bind(ctx, cfg))
+	assert.Error(t, Bind(ctx, (*config)(nil)))
+	var unsupported struct {
+		C chan int `env:"NAME"`
+	}
+	assert.EqualError(t,
+		Bind(Onto(ctx, NewMap(map[string]string{"NAME": "x"})), &unsupported),
+		"env: NAME: unsupported type chan int")
+}
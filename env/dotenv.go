@@ -0,0 +1,304 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// SyntaxError reports a malformed dotenv file.
+type SyntaxError struct {
+	File string
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("env: dotenv line %d: %s", e.Line, e.Msg)
+	}
+	return fmt.Sprintf("env: dotenv %s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// ParseDotenv parses dotenv content from r into a new Map. Each line has the
+// form KEY=VALUE, optionally preceded by "export". Blank lines and lines
+// starting with # are ignored.
+//
+// Unquoted values are trimmed and end at a # preceded by whitespace. Values in
+// single quotes are taken literally. Values in double quotes support the
+// escapes \n, \r, \t, \", \\ and \$. Quoted values may span multiple lines.
+//
+// $VAR and ${VAR} in unquoted and double-quoted values are expanded as by
+// ExpandEnv, using variables defined earlier in the file before those in the
+// context Env.
+func ParseDotenv(ctx context.Context, r io.Reader) (*Map, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &dotenvParser{src: string(src), m: NewMap(nil), base: From(ctx)}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.m, nil
+}
+
+// LoadDotenv parses the dotenv file filename as by ParseDotenv.
+func LoadDotenv(ctx context.Context, filename string) (*Map, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := ParseDotenv(ctx, f)
+	if serr, ok := err.(*SyntaxError); ok {
+		serr.File = filename
+	}
+	return m, err
+}
+
+// Apply sets every variable of src in dst, overwriting existing values. To
+// keep existing values instead, use Overlay(dst, src).
+func Apply(dst, src Env) error {
+	for key, value := range vars(src) {
+		if err := dst.Setenv(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type dotenvParser struct {
+	src  string
+	pos  int
+	m    *Map
+	base Env
+}
+
+func (p *dotenvParser) parse() error {
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil
+		}
+		if err := p.assignment(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *dotenvParser) assignment() error {
+	if strings.HasPrefix(p.src[p.pos:], "export") {
+		if rest := p.src[p.pos+len("export"):]; rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			p.pos += len("export")
+			p.skipSpace()
+		}
+	}
+	start := p.pos
+	for !p.eof() && isNameChar(p.src[p.pos], p.pos == start) {
+		p.pos++
+	}
+	key := p.src[start:p.pos]
+	if key == "" {
+		return p.errorf("expected variable name")
+	}
+	p.skipSpace()
+	if p.eof() || p.src[p.pos] != '=' {
+		return p.errorf("expected '=' after %s", key)
+	}
+	p.pos++
+	p.skipSpace()
+
+	var value string
+	var err error
+	switch {
+	case p.eof():
+	case p.src[p.pos] == '\'':
+		value, err = p.singleQuoted()
+	case p.src[p.pos] == '"':
+		value, err = p.doubleQuoted()
+	default:
+		value, err = p.unquoted()
+	}
+	if err != nil {
+		return err
+	}
+	if err := p.endOfLine(); err != nil {
+		return err
+	}
+	return p.m.Setenv(key, value)
+}
+
+func (p *dotenvParser) singleQuoted() (string, error) {
+	start := p.pos
+	end := strings.IndexByte(p.src[start+1:], '\'')
+	if end < 0 {
+		return "", p.errorf("unterminated quoted value")
+	}
+	p.pos = start + 1 + end + 1
+	return p.src[start+1 : start+1+end], nil
+}
+
+func (p *dotenvParser) doubleQuoted() (string, error) {
+	start := p.pos
+	p.pos++
+	var sb strings.Builder
+	for !p.eof() {
+		c := p.src[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return sb.String(), nil
+		case '\\':
+			p.pos++
+			if p.eof() {
+				break
+			}
+			switch e := p.src[p.pos]; e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case '"', '\\', '$':
+				sb.WriteByte(e)
+			default:
+				sb.WriteByte('\\')
+				sb.WriteByte(e)
+			}
+			p.pos++
+		case '$':
+			value, err := p.variable()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(value)
+		default:
+			sb.WriteByte(c)
+			p.pos++
+		}
+	}
+	p.pos = start
+	return "", p.errorf("unterminated quoted value")
+}
+
+func (p *dotenvParser) unquoted() (string, error) {
+	var sb strings.Builder
+	for !p.eof() && p.src[p.pos] != '\n' {
+		c := p.src[p.pos]
+		if c == '#' && p.pos > 0 && (p.src[p.pos-1] == ' ' || p.src[p.pos-1] == '\t') {
+			break
+		}
+		if c == '$' {
+			value, err := p.variable()
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(value)
+			continue
+		}
+		sb.WriteByte(c)
+		p.pos++
+	}
+	return strings.TrimSpace(strings.TrimSuffix(sb.String(), "\r")), nil
+}
+
+// variable expands the $VAR or ${VAR} reference at p.pos. A $ not followed
+// by a name or { is kept literally.
+func (p *dotenvParser) variable() (string, error) {
+	start := p.pos
+	p.pos++
+	rest := p.src[p.pos:]
+	braced := strings.HasPrefix(rest, "{")
+	if braced {
+		rest = rest[1:]
+	}
+	n := 0
+	for n < len(rest) && isNameChar(rest[n], n == 0) {
+		n++
+	}
+	if !braced {
+		if n == 0 {
+			return "$", nil
+		}
+		p.pos += n
+		return p.lookup(rest[:n]), nil
+	}
+	if n == 0 || n == len(rest) || rest[n] != '}' {
+		p.pos = start
+		return "", p.errorf("unterminated ${ reference")
+	}
+	p.pos += 1 + n + 1
+	return p.lookup(rest[:n]), nil
+}
+
+func (p *dotenvParser) lookup(key string) string {
+	if value, has := p.m.LookupEnv(key); has {
+		return value
+	}
+	return getenv(p.base, key)
+}
+
+func (p *dotenvParser) endOfLine() error {
+	p.skipSpace()
+	if !p.eof() && p.src[p.pos] == '#' {
+		p.skipLine()
+	}
+	if !p.eof() && p.src[p.pos] == '\r' {
+		p.pos++
+	}
+	if p.eof() {
+		return nil
+	}
+	if p.src[p.pos] != '\n' {
+		return p.errorf("unexpected %q after value", p.src[p.pos])
+	}
+	p.pos++
+	return nil
+}
+
+// skipBlank skips whitespace, blank lines and comment lines.
+func (p *dotenvParser) skipBlank() {
+	for !p.eof() {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		case '#':
+			p.skipLine()
+		default:
+			return
+		}
+	}
+}
+
+func (p *dotenvParser) skipSpace() {
+	for !p.eof() && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *dotenvParser) skipLine() {
+	if end := strings.IndexByte(p.src[p.pos:], '\n'); end >= 0 {
+		p.pos += end
+	} else {
+		p.pos = len(p.src)
+	}
+}
+
+func (p *dotenvParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *dotenvParser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{
+		Line: 1 + strings.Count(p.src[:p.pos], "\n"),
+		Msg:  fmt.Sprintf(format, args...),
+	}
+}
+
+func isNameChar(c byte, first bool) bool {
+	return c == '_' || 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || !first && '0' <= c && c <= '9'
+}
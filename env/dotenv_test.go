@@ -0,0 +1,92 @@
+package env
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDotenv(t *testing.T) {
+	t.Parallel()
+
+	ctx := Onto(context.Background(), NewMap(map[string]string{"HOME": "/home/me"}))
+	m, err := ParseDotenv(ctx, strings.NewReader(`
+# comment
+PLAIN=value
+SPACED = spaced value   # comment
+HASH=a#b
+EMPTY=
+export EXPORTED=yes
+SINGLE='literal $HOME \n'
+DOUBLE="tab\tquote\"dollar\$HOME"
+MULTI="line 1
+line 2"
+MULTI_SINGLE='a
+b' # trailing comment
+EXPANDED=$HOME/${PLAIN}/$MISSING/$
+QUOTED_EXPANDED="${HOME}"
+CRLF=crlf`+"\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"PLAIN":           "value",
+		"SPACED":          "spaced value",
+		"HASH":            "a#b",
+		"EMPTY":           "",
+		"EXPORTED":        "yes",
+		"SINGLE":          `literal $HOME \n`,
+		"DOUBLE":          "tab\tquote\"dollar$HOME",
+		"MULTI":           "line 1\nline 2",
+		"MULTI_SINGLE":    "a\nb",
+		"EXPANDED":        "/home/me/value//$",
+		"QUOTED_EXPANDED": "/home/me",
+		"CRLF":            "crlf",
+	}, m.env)
+}
+
+func TestParseDotenvErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	for src, msg := range map[string]string{
+		"A=1\n=2":               "env: dotenv line 2: expected variable name",
+		"A 1":                   "env: dotenv line 1: expected '=' after A",
+		"A=1\nB=\"open\n":       "env: dotenv line 2: unterminated quoted value",
+		"A='open":               "env: dotenv line 1: unterminated quoted value",
+		"A=\"x\\":               "env: dotenv line 1: unterminated quoted value",
+		"A=\"x\" y":             `env: dotenv line 1: unexpected 'y' after value`,
+		"A=1\n\n\nB='x'y\n":     `env: dotenv line 4: unexpected 'y' after value`,
+		"A=${FOO\nB={x}\nC=1\n": "env: dotenv line 1: unterminated ${ reference",
+		"A=\"${FOO\"\n":         "env: dotenv line 1: unterminated ${ reference",
+		"A=\n\nB=${}":           "env: dotenv line 3: unterminated ${ reference",
+	} {
+		_, err := ParseDotenv(ctx, strings.NewReader(src))
+		assert.EqualError(t, err, msg, src)
+	}
+}
+
+func TestLoadDotenv(t *testing.T) {
+	t.Parallel()
+
+	ctx := Onto(context.Background(), NewMap(nil))
+	m, err := LoadDotenv(ctx, "testdata/app.env")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080/demo", getenv(m, "APP_URL"))
+
+	dst := NewMap(map[string]string{"APP_NAME": "old", "OTHER": "x"})
+	require.NoError(t, Apply(dst, m))
+	assert.ElementsMatch(t, []string{
+		"APP_NAME=demo", "APP_PORT=8080", "APP_URL=http://localhost:8080/demo", "OTHER=x",
+	}, dst.Environ())
+	assert.ErrorIs(t, Apply(ReadOnly(dst), m), ErrReadOnly)
+
+	// Secrets are copied, not their redacted form.
+	secrets := WithSecrets(NewMap(map[string]string{"DB_PASSWORD": "hunter2"}), "*_PASSWORD")
+	require.NoError(t, Apply(dst, secrets))
+	assert.Equal(t, "hunter2", getenv(dst, "DB_PASSWORD"))
+
+	_, err = LoadDotenv(ctx, "testdata/missing.env")
+	assert.Error(t, err)
+}
@@ -0,0 +1,176 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Expand replaces references to variables in s with their values from the
+// context Env, following POSIX shell parameter expansion:
+//
+//	$VAR, ${VAR}   the value of VAR
+//	${VAR:-word}   word if VAR is unset or empty, otherwise its value
+//	${VAR:=word}   as :-, but also sets VAR to word
+//	${VAR:?word}   an error with message word if VAR is unset or empty
+//	${VAR:+word}   word if VAR is set and not empty, otherwise empty
+//	${#VAR}        the length of the value of VAR in characters
+//
+// Without the colon, the -, =, ? and + forms only test whether VAR is set.
+// Words may themselves contain references, but values are used literally. A
+// backslash escapes a following $, \ or }, and a $ not followed by a name or {
+// is kept literally.
+//
+// Unlike ExpandEnv, a reference to an unset variable is an error wrapping
+// ErrNotSet, unless one of the forms above supplies a value.
+func Expand(ctx context.Context, s string) (string, error) {
+	x := &expander{env: From(ctx)}
+	return x.expand(s)
+}
+
+type expander struct {
+	env Env
+}
+
+func (x *expander) expand(s string) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s) && (s[i+1] == '$' || s[i+1] == '\\' || s[i+1] == '}'):
+			sb.WriteByte(s[i+1])
+			i += 2
+		case c == '$':
+			value, n, err := x.param(s[i:])
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(value)
+			i += n
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	return sb.String(), nil
+}
+
+// param expands the reference at the start of s, which starts with $, and
+// returns its value and length.
+func (x *expander) param(s string) (string, int, error) {
+	if len(s) < 2 || s[1] != '{' {
+		n := nameLen(s[1:])
+		if n == 0 {
+			return "$", 1, nil
+		}
+		value, err := x.require(s[1 : 1+n])
+		return value, 1 + n, err
+	}
+
+	end, err := closingBrace(s)
+	if err != nil {
+		return "", 0, err
+	}
+	body := s[2:end]
+	n := end + 1
+
+	if strings.HasPrefix(body, "#") {
+		name := body[1:]
+		if name == "" || nameLen(name) != len(name) {
+			return "", 0, fmt.Errorf("env: bad substitution %q", s[:n])
+		}
+		value, err := x.require(name)
+		return strconv.Itoa(utf8.RuneCountInString(value)), n, err
+	}
+
+	name := body[:nameLen(body)]
+	rest := body[len(name):]
+	if name == "" {
+		return "", 0, fmt.Errorf("env: bad substitution %q", s[:n])
+	}
+	if rest == "" {
+		value, err := x.require(name)
+		return value, n, err
+	}
+
+	colon := strings.HasPrefix(rest, ":")
+	if colon {
+		rest = rest[1:]
+	}
+	if rest == "" || !strings.ContainsRune("-=?+", rune(rest[0])) {
+		return "", 0, fmt.Errorf("env: bad substitution %q", s[:n])
+	}
+	op, word := rest[0], rest[1:]
+
+	value, has := x.env.LookupEnv(name)
+	missing := !has || colon && value == ""
+	switch op {
+	case '-':
+		if missing {
+			value, err = x.expand(word)
+		}
+	case '=':
+		if missing {
+			if value, err = x.expand(word); err == nil {
+				if err = x.env.Setenv(name, value); err != nil {
+					err = &VarError{Key: name, Err: err}
+				}
+			}
+		}
+	case '?':
+		if missing {
+			var msg string
+			if msg, err = x.expand(word); err == nil {
+				err = ErrNotSet
+				if msg != "" {
+					err = errors.New(msg)
+				}
+				err = &VarError{Key: name, Err: err}
+			}
+		}
+	case '+':
+		value = ""
+		if !missing {
+			value, err = x.expand(word)
+		}
+	}
+	return value, n, err
+}
+
+// require returns the value of name, which must be set.
+func (x *expander) require(name string) (string, error) {
+	value, has := x.env.LookupEnv(name)
+	if !has {
+		return "", &VarError{Key: name, Err: ErrNotSet}
+	}
+	return value, nil
+}
+
+// closingBrace returns the index of the } matching the ${ at the start of s.
+func closingBrace(s string) (int, error) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case strings.HasPrefix(s[i:], "${"):
+			depth++
+			i++
+		case s[i] == '}':
+			if depth--; depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("env: unterminated reference %q", s)
+}
+
+func nameLen(s string) int {
+	n := 0
+	for n < len(s) && isNameChar(s[n], n == 0) {
+		n++
+	}
+	return n
+}
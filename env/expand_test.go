@@ -0,0 +1,94 @@
+package env
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	m := NewMap(map[string]string{
+		"HOME":  "/home/me",
+		"EMPTY": "",
+		"CACHE": "$HOME/.cache",
+		"PASS":  "pa$word",
+		"UNI":   "héllo",
+	})
+	ctx := Onto(context.Background(), m)
+
+	for s, expected := range map[string]string{
+		"plain":                      "plain",
+		"$HOME ${HOME}":              "/home/me /home/me",
+		"${CACHE}/x":                 "$HOME/.cache/x",
+		"$PASS ${MISSING:-$PASS}":    "pa$word pa$word",
+		"${EMPTY:-d} ${EMPTY-d}":     "d ",
+		"${MISSING:-d} ${MISSING-d}": "d d",
+		"${MISSING:-${HOME}/d}":      "/home/me/d",
+		"${MISSING:-${OTHER:-x}}":    "x",
+		"${HOME:+set} ${EMPTY:+set}": "set ",
+		"${EMPTY+set}${MISSING+set}": "set",
+		"${HOME:?required}":          "/home/me",
+		"${#UNI} ${#EMPTY}":          "5 0",
+		`\$HOME \\ \} $ $1 a$`:       `$HOME \ } $ $1 a$`,
+		`${MISSING:-a\}b}`:           "a}b",
+		`\n`:                         `\n`,
+	} {
+		actual, err := Expand(ctx, s)
+		if assert.NoError(t, err, s) {
+			assert.Equal(t, expected, actual, s)
+		}
+	}
+}
+
+func TestExpandAssign(t *testing.T) {
+	t.Parallel()
+
+	m := NewMap(map[string]string{"EMPTY": ""})
+	ctx := Onto(context.Background(), m)
+
+	s, err := Expand(ctx, "${A:=${B=b}} ${EMPTY=x} ${EMPTY:=y}")
+	assert.NoError(t, err)
+	assert.Equal(t, "b  y", s)
+	assert.ElementsMatch(t, []string{"A=b", "B=b", "EMPTY=y"}, m.Environ())
+
+	_, err = Expand(Onto(ctx, ReadOnly(m)), "${C:=c}")
+	assert.ErrorIs(t, err, ErrReadOnly)
+}
+
+func TestExpandErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := Onto(context.Background(), NewMap(map[string]string{
+		"EMPTY": "",
+	}))
+
+	for s, msg := range map[string]string{
+		"$MISSING":                  "env: MISSING: not set",
+		"${MISSING}":                "env: MISSING: not set",
+		"${#MISSING}":               "env: MISSING: not set",
+		"${EMPTY:?must be set}":     "env: EMPTY: must be set",
+		"${MISSING?}":               "env: MISSING: not set",
+		"${MISSING:-$OTHER}":        "env: OTHER: not set",
+		"${MISSING:?$OTHER}":        "env: OTHER: not set",
+		"${MISSING:=$OTHER}":        "env: OTHER: not set",
+		"${MISSING:+x}${EMPTY:+$X}": "",
+		"${HOME":                    `env: unterminated reference "${HOME"`,
+		"${}":                       `env: bad substitution "${}"`,
+		"${#}":                      `env: bad substitution "${#}"`,
+		"${HOME:}":                  `env: bad substitution "${HOME:}"`,
+		"${HOME/x/y}":               `env: bad substitution "${HOME/x/y}"`,
+	} {
+		_, err := Expand(ctx, s)
+		if msg == "" {
+			assert.NoError(t, err, s)
+		} else {
+			assert.EqualError(t, err, msg, s)
+		}
+	}
+
+	_, err := Expand(ctx, "$MISSING")
+	assert.ErrorIs(t, err, ErrNotSet)
+}
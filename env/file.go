@@ -0,0 +1,116 @@
+package env
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anz-bank/pkg/clock"
+)
+
+// File is an Observable Env backed by a dotenv or JSON file, which it reloads
+// whenever the file changes. Files with a .json extension must hold a single
+// object with string values. Other files are parsed by ParseDotenv.
+//
+// Changes made with Setenv and Unsetenv only last until the next reload.
+// Watch the File to react to reloads, for example to apply a new log level.
+type File struct {
+	*Observable
+	ctx      context.Context
+	filename string
+
+	mux     sync.Mutex
+	modTime time.Time
+	size    int64
+	err     error
+}
+
+// LoadFile loads filename and then polls it for changes every interval on the
+// context clock until ctx is done. It returns an error if the initial load
+// fails.
+func LoadFile(ctx context.Context, filename string, interval time.Duration) (*File, error) {
+	f := &File{
+		Observable: NewObservable(NewMap(nil)),
+		ctx:        ctx,
+		filename:   filename,
+	}
+	if err := f.Reload(); err != nil {
+		return nil, err
+	}
+	ticker := clock.NewTicker(ctx, interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				f.reloadIfChanged()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return f, nil
+}
+
+// Err returns the error from the most recent reload, if it failed. The
+// previously loaded values are kept when a reload fails.
+func (f *File) Err() error {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return f.err
+}
+
+// Reload reloads the file immediately.
+func (f *File) Reload() error {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	info, err := os.Stat(f.filename)
+	if err == nil {
+		f.modTime, f.size = info.ModTime(), info.Size()
+		err = f.load()
+	}
+	f.err = err
+	return err
+}
+
+func (f *File) reloadIfChanged() {
+	info, err := os.Stat(f.filename)
+	if err == nil {
+		f.mux.Lock()
+		unchanged := info.ModTime().Equal(f.modTime) && info.Size() == f.size
+		f.mux.Unlock()
+		if unchanged {
+			return
+		}
+	}
+	_ = f.Reload()
+}
+
+func (f *File) load() error {
+	data, err := os.ReadFile(f.filename)
+	if err != nil {
+		return err
+	}
+	vars := map[string]string{}
+	if strings.EqualFold(filepath.Ext(f.filename), ".json") {
+		if err := json.Unmarshal(data, &vars); err != nil {
+			return fmt.Errorf("env: %s: %w", f.filename, err)
+		}
+	} else {
+		m, err := ParseDotenv(f.ctx, bytes.NewReader(data))
+		if err != nil {
+			if serr, ok := err.(*SyntaxError); ok {
+				serr.File = f.filename
+			}
+			return err
+		}
+		vars = m.env
+	}
+	return f.replace(vars)
+}
@@ -0,0 +1,90 @@
+package env
+
+import (
+	"strings"
+	"sync"
+)
+
+// overlay implements an Env that layers top over base.
+type overlay struct {
+	top, base Env
+
+	mux      sync.RWMutex
+	cleared  bool
+	whiteout map[string]bool
+}
+
+// Overlay returns an Env that looks up variables in top, falling back to base.
+// All changes are made to top. Variables unset or cleared through the overlay
+// are hidden from base as well, so base is never modified.
+func Overlay(top, base Env) Env {
+	return &overlay{top: top, base: base, whiteout: map[string]bool{}}
+}
+
+func (o *overlay) Clearenv() {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	o.top.Clearenv()
+	o.cleared = true
+	o.whiteout = map[string]bool{}
+}
+
+func (o *overlay) Environ() []string {
+	o.mux.RLock()
+	defer o.mux.RUnlock()
+	result := o.top.Environ()
+	if o.cleared {
+		return result
+	}
+	seen := make(map[string]bool, len(result))
+	for _, kv := range result {
+		key, _, _ := strings.Cut(kv, "=")
+		seen[key] = true
+	}
+	for _, kv := range o.base.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		if !seen[key] && !o.whiteout[key] {
+			result = append(result, kv)
+		}
+	}
+	return result
+}
+
+func (o *overlay) LookupEnv(key string) (string, bool) {
+	o.mux.RLock()
+	defer o.mux.RUnlock()
+	if value, has := o.top.LookupEnv(key); has {
+		return value, true
+	}
+	if o.cleared || o.whiteout[key] {
+		return "", false
+	}
+	return o.base.LookupEnv(key)
+}
+
+func (o *overlay) Setenv(key, value string) error {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	if err := o.top.Setenv(key, value); err != nil {
+		return err
+	}
+	delete(o.whiteout, key)
+	return nil
+}
+
+func (o *overlay) Unsetenv(key string) error {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	if err := o.top.Unsetenv(key); err != nil {
+		return err
+	}
+	if !o.cleared {
+		o.whiteout[key] = true
+	}
+	return nil
+}
+
+// IsSecret reports whether key is secret in top or base.
+func (o *overlay) IsSecret(key string) bool {
+	return isSecret(o.top, key) || isSecret(o.base, key)
+}
@@ -0,0 +1,84 @@
+package env
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverlay(t *testing.T) {
+	t.Parallel()
+
+	base := NewMap(map[string]string{"A": "base-a", "B": "base-b"})
+	top := NewMap(map[string]string{"B": "top-b"})
+	ctx := Onto(context.Background(), Overlay(top, base))
+
+	assert.Equal(t, "base-a", Getenv(ctx, "A"))
+	assert.Equal(t, "top-b", Getenv(ctx, "B"))
+	assert.ElementsMatch(t, []string{"A=base-a", "B=top-b"}, Environ(ctx))
+
+	assert.NoError(t, Setenv(ctx, "A", "top-a"))
+	assert.Equal(t, "top-a", Getenv(ctx, "A"))
+
+	// Unsetting hides base without modifying it.
+	assert.NoError(t, Unsetenv(ctx, "A"))
+	_, has := LookupEnv(ctx, "A")
+	assert.False(t, has)
+	assert.ElementsMatch(t, []string{"B=top-b"}, Environ(ctx))
+	assert.NoError(t, Setenv(ctx, "A", "again"))
+	assert.Equal(t, "again", Getenv(ctx, "A"))
+
+	Clearenv(ctx)
+	assert.Empty(t, Environ(ctx))
+	_, has = LookupEnv(ctx, "B")
+	assert.False(t, has)
+	assert.NoError(t, Setenv(ctx, "C", "c"))
+	assert.NoError(t, Unsetenv(ctx, "C"))
+	assert.Empty(t, Environ(ctx))
+
+	assert.ElementsMatch(t, []string{"A=base-a", "B=base-b"}, base.Environ())
+}
+
+func TestOverlaySystem(t *testing.T) {
+	t.Parallel()
+
+	ctx := Onto(context.Background(), Overlay(NewMap(nil), From(context.Background())))
+	assert.NoError(t, Setenv(ctx, "PATH", "overridden"))
+	assert.Equal(t, "overridden", Getenv(ctx, "PATH"))
+	assert.NotEqual(t, "overridden", Getenv(context.Background(), "PATH"))
+}
+
+func TestReadOnly(t *testing.T) {
+	t.Parallel()
+
+	m := NewMap(map[string]string{"A": "a"})
+	ctx := Onto(context.Background(), ReadOnly(m))
+
+	assert.Equal(t, "a", Getenv(ctx, "A"))
+	assert.Equal(t, []string{"A=a"}, Environ(ctx))
+	assert.ErrorIs(t, Setenv(ctx, "A", "b"), ErrReadOnly)
+	assert.EqualError(t, Unsetenv(ctx, "A"), "env: read-only environment: cannot unset A")
+	Clearenv(ctx)
+	assert.Equal(t, "a", Getenv(ctx, "A"))
+}
+
+func TestPrefixed(t *testing.T) {
+	t.Parallel()
+
+	m := NewMap(map[string]string{"APP_PORT": "8080", "APP_HOST": "localhost", "PORT": "80"})
+	ctx := Onto(context.Background(), Prefixed(m, "APP_"))
+
+	assert.Equal(t, "8080", Getenv(ctx, "PORT"))
+	_, has := LookupEnv(ctx, "APP_PORT")
+	assert.False(t, has)
+	assert.ElementsMatch(t, []string{"PORT=8080", "HOST=localhost"}, Environ(ctx))
+
+	assert.NoError(t, Setenv(ctx, "DEBUG", "1"))
+	assert.Equal(t, "1", getenv(m, "APP_DEBUG"))
+	assert.NoError(t, Unsetenv(ctx, "HOST"))
+	assert.ElementsMatch(t, []string{"APP_PORT=8080", "APP_DEBUG=1", "PORT=80"}, m.Environ())
+
+	Clearenv(ctx)
+	assert.Equal(t, []string{"PORT=80"}, m.Environ())
+}
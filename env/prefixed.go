@@ -0,0 +1,51 @@
+package env
+
+import "strings"
+
+type prefixed struct {
+	env    Env
+	prefix string
+}
+
+// Prefixed returns a view of the variables in e whose names start with
+// prefix, with the prefix removed. For example, with the prefix "APP_", the
+// variable APP_PORT is seen as PORT. Variables set through the view are set in
+// e with the prefix added, and Clearenv only removes prefixed variables.
+func Prefixed(e Env, prefix string) Env {
+	return prefixed{env: e, prefix: prefix}
+}
+
+func (p prefixed) Clearenv() {
+	for _, kv := range p.env.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(key, p.prefix) {
+			_ = p.env.Unsetenv(key)
+		}
+	}
+}
+
+func (p prefixed) Environ() []string {
+	var result []string
+	for _, kv := range p.env.Environ() {
+		if strings.HasPrefix(kv, p.prefix) {
+			result = append(result, kv[len(p.prefix):])
+		}
+	}
+	return result
+}
+
+func (p prefixed) LookupEnv(key string) (string, bool) {
+	return p.env.LookupEnv(p.prefix + key)
+}
+
+func (p prefixed) Setenv(key, value string) error {
+	return p.env.Setenv(p.prefix+key, value)
+}
+
+func (p prefixed) Unsetenv(key string) error {
+	return p.env.Unsetenv(p.prefix + key)
+}
+
+func (p prefixed) IsSecret(key string) bool {
+	return isSecret(p.env, p.prefix+key)
+}
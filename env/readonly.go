@@ -0,0 +1,41 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrReadOnly is returned when modifying an Env created by ReadOnly.
+var ErrReadOnly = errors.New("env: read-only environment")
+
+type readOnly struct {
+	env Env
+}
+
+// ReadOnly returns a view of e that cannot be modified. Setenv and Unsetenv
+// return an error wrapping ErrReadOnly and Clearenv does nothing.
+func ReadOnly(e Env) Env {
+	return readOnly{env: e}
+}
+
+func (readOnly) Clearenv() {}
+
+func (r readOnly) Environ() []string {
+	return r.env.Environ()
+}
+
+func (r readOnly) LookupEnv(key string) (string, bool) {
+	return r.env.LookupEnv(key)
+}
+
+func (readOnly) Setenv(key, _ string) error {
+	return fmt.Errorf("%w: cannot set %s", ErrReadOnly, key)
+}
+
+func (readOnly) Unsetenv(key string) error {
+	return fmt.Errorf("%w: cannot unset %s", ErrReadOnly, key)
+}
+
+func (r readOnly) IsSecret(key string) bool {
+	return isSecret(r.env, key)
+}
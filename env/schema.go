@@ -0,0 +1,275 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// ErrUndeclared is wrapped by errors about variables not declared in a
+// Schema.
+var ErrUndeclared = errors.New("undeclared variable")
+
+// Type is the type of value a declared variable holds.
+type Type int
+
+// The supported Types.
+const (
+	TypeString Type = iota
+	TypeInt
+	TypeBool
+	TypeDuration
+	TypeFloat
+	TypeURL
+)
+
+var typeNames = []string{"string", "int", "bool", "duration", "float", "url"}
+
+func (t Type) String() string {
+	if int(t) < len(typeNames) {
+		return typeNames[t]
+	}
+	return fmt.Sprintf("Type(%d)", int(t))
+}
+
+func (t Type) parse(s string) error {
+	var err error
+	switch t {
+	case TypeString:
+	case TypeInt:
+		_, err = strconv.Atoi(s)
+	case TypeBool:
+		_, err = strconv.ParseBool(s)
+	case TypeDuration:
+		_, err = time.ParseDuration(s)
+	case TypeFloat:
+		_, err = parseFloat(s)
+	case TypeURL:
+		_, err = url.Parse(s)
+	default:
+		err = fmt.Errorf("unknown type %v", t)
+	}
+	return err
+}
+
+// Var declares an environment variable.
+type Var struct {
+	Name string
+	Type Type
+	// Default is used when the variable is not set. It is expanded as by
+	// Expand, so it may refer to other variables, as in "${HOME:-/tmp}", but
+	// may not set them. An empty Default means there is none unless
+	// HasDefault is set.
+	Default string
+	// HasDefault declares an empty Default. It is implied by a non-empty
+	// Default.
+	HasDefault  bool
+	Description string
+	Required    bool
+}
+
+// Schema declares the environment variables a program uses.
+type Schema struct {
+	vars  []Var
+	index map[string]int
+}
+
+// NewSchema creates a Schema declaring vars. It panics if a name is declared
+// more than once.
+func NewSchema(vars ...Var) *Schema {
+	s := &Schema{vars: vars, index: make(map[string]int, len(vars))}
+	for i, v := range vars {
+		if _, has := s.index[v.Name]; has {
+			panic(fmt.Sprintf("env: %s declared more than once", v.Name))
+		}
+		s.index[v.Name] = i
+	}
+	return s
+}
+
+// Lookup returns the declaration of name.
+func (s *Schema) Lookup(name string) (Var, bool) {
+	if i, has := s.index[name]; has {
+		return s.vars[i], true
+	}
+	return Var{}, false
+}
+
+// Validate checks the context Env against s without modifying it. It reports
+// every required variable that is neither set nor defaulted, every default
+// that fails to expand, and every value or default that does not parse as its
+// declared type, as a *BindError.
+func (s *Schema) Validate(ctx context.Context) error {
+	e := From(ctx)
+	var errs []error
+	for _, v := range s.vars {
+		value, has, err := s.lookup(e, v)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !has {
+			if v.Required {
+				errs = append(errs, &VarError{Key: v.Name, Err: ErrNotSet})
+			}
+			continue
+		}
+		if err := v.Type.parse(value); err != nil {
+			errs = append(errs, &VarError{Key: v.Name, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return &BindError{Errors: errs}
+	}
+	return nil
+}
+
+// WriteHelp writes a table describing the declared variables to w.
+func (s *Schema) WriteHelp(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VARIABLE\tTYPE\tDEFAULT\tREQUIRED\tDESCRIPTION")
+	for _, v := range s.vars {
+		required := "no"
+		if v.Required {
+			required = "yes"
+		}
+		def := v.Default
+		if def == "" && v.HasDefault {
+			def = `""`
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", v.Name, v.Type, def, required, v.Description)
+	}
+	return tw.Flush()
+}
+
+// lookup returns the value of v in e, or its expanded default if it is not
+// set.
+func (s *Schema) lookup(e Env, v Var) (string, bool, error) {
+	if value, has := e.LookupEnv(v.Name); has {
+		return value, true, nil
+	}
+	if v.Default == "" && !v.HasDefault {
+		return "", false, nil
+	}
+	value, err := (&expander{env: ReadOnly(e)}).expand(v.Default)
+	if err != nil {
+		return "", false, &VarError{Key: v.Name, Err: fmt.Errorf("default %q: %w", v.Default, err)}
+	}
+	return value, true, nil
+}
+
+type strictOptions struct {
+	panic bool
+}
+
+// StrictOption is used to configure a StrictEnv. The *Undeclared functions
+// should be used to obtain options for passing to Schema.Strict.
+type StrictOption func(*strictOptions)
+
+// PanicOnUndeclared returns a StrictOption that makes looking up an
+// undeclared variable panic. This suits tests.
+func PanicOnUndeclared() StrictOption {
+	return func(o *strictOptions) {
+		o.panic = true
+	}
+}
+
+// StrictEnv is an Env restricted to the variables declared in a Schema.
+type StrictEnv struct {
+	strictOptions
+	env    Env
+	schema *Schema
+
+	mux  sync.Mutex
+	errs []error
+}
+
+var _ Env = &StrictEnv{}
+
+// Strict returns a view of e restricted to the variables declared in s.
+// Declared variables that are not set take their default values. Looking up
+// an undeclared variable, or one whose default fails to expand, reports it as
+// not set and records an error, returned by Err. Setting or unsetting an
+// undeclared variable returns an error.
+func (s *Schema) Strict(e Env, options ...StrictOption) *StrictEnv {
+	se := &StrictEnv{env: e, schema: s}
+	for _, option := range options {
+		option(&se.strictOptions)
+	}
+	return se
+}
+
+// Err returns a *BindError listing the errors recorded by lookups so far, or
+// nil if there were none.
+func (se *StrictEnv) Err() error {
+	se.mux.Lock()
+	defer se.mux.Unlock()
+	if len(se.errs) == 0 {
+		return nil
+	}
+	return &BindError{Errors: append([]error(nil), se.errs...)}
+}
+
+func (se *StrictEnv) Clearenv() {
+	for _, v := range se.schema.vars {
+		_ = se.env.Unsetenv(v.Name)
+	}
+}
+
+func (se *StrictEnv) Environ() []string {
+	var result []string
+	for _, v := range se.schema.vars {
+		if value, has, err := se.schema.lookup(se.env, v); err == nil && has {
+			result = append(result, v.Name+"="+value)
+		}
+	}
+	return result
+}
+
+func (se *StrictEnv) LookupEnv(key string) (string, bool) {
+	v, declared := se.schema.Lookup(key)
+	if !declared {
+		err := &VarError{Key: key, Err: ErrUndeclared}
+		if se.panic {
+			panic(err)
+		}
+		se.record(err)
+		return "", false
+	}
+	value, has, err := se.schema.lookup(se.env, v)
+	if err != nil {
+		se.record(err)
+	}
+	return value, has
+}
+
+func (se *StrictEnv) Setenv(key, value string) error {
+	if _, declared := se.schema.Lookup(key); !declared {
+		return &VarError{Key: key, Err: ErrUndeclared}
+	}
+	return se.env.Setenv(key, value)
+}
+
+func (se *StrictEnv) Unsetenv(key string) error {
+	if _, declared := se.schema.Lookup(key); !declared {
+		return &VarError{Key: key, Err: ErrUndeclared}
+	}
+	return se.env.Unsetenv(key)
+}
+
+func (se *StrictEnv) record(err error) {
+	se.mux.Lock()
+	defer se.mux.Unlock()
+	se.errs = append(se.errs, err)
+}
+
+// IsSecret reports whether key is secret in the wrapped Env.
+func (se *StrictEnv) IsSecret(key string) bool {
+	return isSecret(se.env, key)
+}
@@ -0,0 +1,123 @@
+package env
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = NewSchema(
+	Var{Name: "HOME", Description: "Home directory"},
+	Var{Name: "PORT", Type: TypeInt, Default: "8080", Description: "Listen port"},
+	Var{Name: "CACHE_DIR", Default: "${HOME:-/tmp}/.cache"},
+	Var{Name: "TIMEOUT", Type: TypeDuration, Required: true, Description: "Request timeout"},
+	Var{Name: "DEBUG", Type: TypeBool},
+	Var{Name: "SUFFIX", HasDefault: true, Required: true},
+)
+
+func TestSchemaValidate(t *testing.T) {
+	t.Parallel()
+
+	ctx := Onto(context.Background(), NewMap(map[string]string{"TIMEOUT": "5s", "UNDECLARED": "x"}))
+	assert.NoError(t, testSchema.Validate(ctx))
+	assert.Equal(t, []string{"TIMEOUT=5s", "UNDECLARED=x"}, sortedEnviron(From(ctx)))
+
+	ctx = Onto(ctx, NewMap(map[string]string{"PORT": "http", "DEBUG": "maybe"}))
+	err := testSchema.Validate(ctx)
+	var bindErr *BindError
+	require.ErrorAs(t, err, &bindErr)
+	assert.Len(t, bindErr.Errors, 3)
+	assert.Contains(t, err.Error(), `env: PORT: strconv.Atoi: parsing "http": invalid syntax; env: TIMEOUT: not set; `)
+
+	// Defaults that fail to expand are reported, and may not set variables.
+	m := NewMap(nil)
+	err = NewSchema(
+		Var{Name: "A", Default: "$MISSING"},
+		Var{Name: "B", Default: "${MISSING:=x}"},
+	).Validate(Onto(ctx, m))
+	require.ErrorAs(t, err, &bindErr)
+	if assert.Len(t, bindErr.Errors, 2) {
+		assert.EqualError(t, bindErr.Errors[0], `env: A: default "$MISSING": env: MISSING: not set`)
+		assert.ErrorIs(t, bindErr.Errors[0], ErrNotSet)
+		assert.ErrorIs(t, bindErr.Errors[1], ErrReadOnly)
+	}
+	assert.Empty(t, m.Environ())
+
+	v, has := testSchema.Lookup("PORT")
+	assert.True(t, has)
+	assert.Equal(t, TypeInt, v.Type)
+	_, has = testSchema.Lookup("UNDECLARED")
+	assert.False(t, has)
+
+	assert.Panics(t, func() { NewSchema(Var{Name: "A"}, Var{Name: "A"}) })
+	assert.Equal(t, "Type(99)", Type(99).String())
+}
+
+func TestSchemaStrict(t *testing.T) {
+	t.Parallel()
+
+	m := NewMap(map[string]string{"HOME": "/home/me", "UNDECLARED": "x"})
+	strict := testSchema.Strict(m)
+	ctx := Onto(context.Background(), strict)
+
+	assert.Equal(t, "8080", Getenv(ctx, "PORT"))
+	assert.Equal(t, "/home/me/.cache", Getenv(ctx, "CACHE_DIR"))
+	assert.Equal(t, "/tmp/.cache", Getenv(Onto(ctx, testSchema.Strict(NewMap(nil))), "CACHE_DIR"))
+	_, has := LookupEnv(ctx, "DEBUG")
+	assert.False(t, has)
+	suffix, has := LookupEnv(ctx, "SUFFIX")
+	assert.True(t, has)
+	assert.Empty(t, suffix)
+	assert.ElementsMatch(t, []string{"HOME=/home/me", "PORT=8080", "CACHE_DIR=/home/me/.cache", "SUFFIX="}, Environ(ctx))
+	assert.Equal(t, "home=/home/me", ExpandEnv(ctx, "home=$HOME"))
+	assert.NoError(t, strict.Err())
+
+	_, has = LookupEnv(ctx, "UNDECLARED")
+	assert.False(t, has)
+	assert.EqualError(t, strict.Err(), "env: UNDECLARED: undeclared variable")
+	assert.ErrorIs(t, Setenv(ctx, "UNDECLARED", "y"), ErrUndeclared)
+	assert.ErrorIs(t, Unsetenv(ctx, "UNDECLARED"), ErrUndeclared)
+
+	require.NoError(t, Setenv(ctx, "PORT", "9090"))
+	assert.Equal(t, "9090", getenv(m, "PORT"))
+	require.NoError(t, Unsetenv(ctx, "PORT"))
+	assert.Equal(t, "8080", Getenv(ctx, "PORT"))
+
+	Clearenv(ctx)
+	assert.Equal(t, []string{"UNDECLARED=x"}, m.Environ())
+
+	ctx = Onto(ctx, testSchema.Strict(m, PanicOnUndeclared()))
+	assert.Panics(t, func() { Getenv(ctx, "UNDECLARED") })
+
+	bad := NewSchema(Var{Name: "A", Default: "$MISSING"}).Strict(m)
+	_, has = bad.LookupEnv("A")
+	assert.False(t, has)
+	assert.Empty(t, bad.Environ())
+	assert.ErrorIs(t, bad.Err(), ErrNotSet)
+}
+
+func TestSchemaWriteHelp(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, testSchema.WriteHelp(&buf))
+	assert.Equal(t, ""+
+		"VARIABLE   TYPE      DEFAULT               REQUIRED  DESCRIPTION\n"+
+		"HOME       string                          no        Home directory\n"+
+		"PORT       int       8080                  no        Listen port\n"+
+		"CACHE_DIR  string    ${HOME:-/tmp}/.cache  no        \n"+
+		"TIMEOUT    duration                        yes       Request timeout\n"+
+		"DEBUG      bool                            no        \n"+
+		"SUFFIX     string    \"\"                    yes       \n",
+		buf.String())
+}
+
+func sortedEnviron(e Env) []string {
+	environ := e.Environ()
+	sort.Strings(environ)
+	return environ
+}
@@ -0,0 +1,115 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Redacted replaces secret values wherever they are formatted.
+const Redacted = "***"
+
+// Secret holds a sensitive value. Its String, fmt, JSON and text forms are all
+// Redacted, so a Secret can be logged or serialised without leaking the
+// value. Use Reveal to get the value itself.
+type Secret struct {
+	value string
+}
+
+// NewSecret returns a Secret holding value.
+func NewSecret(value string) Secret {
+	return Secret{value: value}
+}
+
+// Reveal returns the secret value.
+func (s Secret) Reveal() string {
+	return s.value
+}
+
+func (Secret) String() string {
+	return Redacted
+}
+
+func (Secret) GoString() string {
+	return Redacted
+}
+
+// Format implements fmt.Formatter so that every verb is redacted.
+func (Secret) Format(f fmt.State, _ rune) {
+	_, _ = f.Write([]byte(Redacted))
+}
+
+func (Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + Redacted + `"`), nil
+}
+
+func (Secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// DecodeEnv implements Decoder so that Secret fields can be populated by Bind.
+func (s *Secret) DecodeEnv(value string) error {
+	s.value = value
+	return nil
+}
+
+// secretEnv implements an Env in which some variables are secret.
+type secretEnv struct {
+	Env
+	patterns []string
+	names    map[string]bool
+}
+
+// WithSecrets returns a view of e in which variables matching any of patterns
+// are secret. Patterns use path.Match syntax, e.g. "*_PASSWORD", and plain
+// names match only themselves. Secret values are redacted in Environ, but are
+// returned as is by LookupEnv. Use LookupSecret to read them as Secrets.
+func WithSecrets(e Env, patterns ...string) Env {
+	return &secretEnv{Env: e, patterns: patterns}
+}
+
+func (s *secretEnv) Environ() []string {
+	result := s.Env.Environ()
+	for i, kv := range result {
+		if key, _, _ := strings.Cut(kv, "="); s.IsSecret(key) {
+			result[i] = key + "=" + Redacted
+		}
+	}
+	return result
+}
+
+// IsSecret reports whether key is one of the secret names, matches any of the
+// secret patterns, or is secret in the underlying Env.
+func (s *secretEnv) IsSecret(key string) bool {
+	if s.names[key] {
+		return true
+	}
+	for _, pattern := range s.patterns {
+		if matched, err := path.Match(pattern, key); matched || err != nil && pattern == key {
+			return true
+		}
+	}
+	return isSecret(s.Env, key)
+}
+
+// isSecret reports whether key is secret in e. Wrappers in this package
+// implement IsSecret by forwarding to the Envs they wrap, so that secrets stay
+// marked however they are layered.
+func isSecret(e Env, key string) bool {
+	if s, ok := e.(interface{ IsSecret(key string) bool }); ok {
+		return s.IsSecret(key)
+	}
+	return false
+}
+
+// IsSecret reports whether the variable key is secret in the context Env.
+func IsSecret(ctx context.Context, key string) bool {
+	return isSecret(From(ctx), key)
+}
+
+// LookupSecret returns the variable key from the context Env as a Secret.
+func LookupSecret(ctx context.Context, key string) (Secret, bool) {
+	value, has := LookupEnv(ctx, key)
+	return NewSecret(value), has
+}
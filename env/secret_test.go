@@ -0,0 +1,94 @@
+package env
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecret(t *testing.T) {
+	t.Parallel()
+
+	s := NewSecret("hunter2")
+	assert.Equal(t, "hunter2", s.Reveal())
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%10s"} {
+		assert.Equal(t, Redacted, fmt.Sprintf(format, s), format)
+		assert.Equal(t, Redacted, fmt.Sprintf(format, &s), format)
+	}
+	assert.Equal(t, Redacted, s.String())
+
+	data, err := json.Marshal(struct {
+		Password Secret
+		Token    *Secret
+		Keys     map[Secret]int
+	}{s, &s, map[Secret]int{s: 1}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Password":"***","Token":"***","Keys":{"***":1}}`, string(data))
+}
+
+func TestWithSecrets(t *testing.T) {
+	t.Parallel()
+
+	m := NewMap(map[string]string{
+		"DB_PASSWORD": "hunter2",
+		"API_TOKEN":   "abc",
+		"HOST":        "localhost",
+		"[":           "odd",
+	})
+	ctx := Onto(context.Background(), WithSecrets(m, "*_PASSWORD", "API_TOKEN", "["))
+
+	assert.ElementsMatch(t, []string{
+		"DB_PASSWORD=***", "API_TOKEN=***", "HOST=localhost", "[=***",
+	}, Environ(ctx))
+	assert.Equal(t, "hunter2", Getenv(ctx, "DB_PASSWORD"))
+	assert.True(t, IsSecret(ctx, "DB_PASSWORD"))
+	assert.False(t, IsSecret(ctx, "HOST"))
+	assert.False(t, IsSecret(Onto(ctx, m), "DB_PASSWORD"))
+
+	// Wrappers see secrets declared further down.
+	ctx = Onto(ctx, WithSecrets(From(ctx), "HOST"))
+	assert.True(t, IsSecret(ctx, "API_TOKEN"))
+	assert.ElementsMatch(t, []string{
+		"DB_PASSWORD=***", "API_TOKEN=***", "HOST=***", "[=***",
+	}, Environ(ctx))
+
+	s, has := LookupSecret(ctx, "API_TOKEN")
+	assert.True(t, has)
+	assert.Equal(t, "abc", s.Reveal())
+
+	var cfg struct {
+		Password Secret `env:"DB_PASSWORD"`
+	}
+	require.NoError(t, Bind(ctx, &cfg))
+	assert.Equal(t, "hunter2", cfg.Password.Reveal())
+}
+
+func TestSecretsThroughWrappers(t *testing.T) {
+	t.Parallel()
+
+	secrets := WithSecrets(NewMap(map[string]string{"APP_PW": "hunter2"}), "APP_*")
+	schema := NewSchema(Var{Name: "APP_PW"})
+	for name, e := range map[string]Env{
+		"Overlay":       Overlay(NewMap(nil), secrets),
+		"Overlay top":   Overlay(secrets, NewMap(nil)),
+		"ReadOnly":      ReadOnly(secrets),
+		"Auditor":       NewAuditor(secrets),
+		"Observable":    NewObservable(secrets),
+		"StrictEnv":     schema.Strict(secrets),
+		"Nested":        ReadOnly(NewAuditor(Overlay(NewMap(nil), secrets))),
+		"Prefixed":      Prefixed(secrets, "APP_"),
+		"Prefixed miss": Prefixed(secrets, "OTHER_"),
+	} {
+		key := "APP_PW"
+		if strings.HasPrefix(name, "Prefixed") {
+			key = "PW"
+		}
+		assert.Equal(t, name != "Prefixed miss", IsSecret(Onto(context.Background(), e), key), name)
+	}
+	assert.False(t, IsSecret(Onto(context.Background(), ReadOnly(NewMap(nil))), "APP_PW"))
+}
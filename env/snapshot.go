@@ -0,0 +1,108 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Snapshot returns an immutable copy of the context Env. Values are read with
+// LookupEnv, so secrets redacted by Environ are copied intact, and variables
+// secret in the context Env stay secret in the copy.
+func Snapshot(ctx context.Context) Env {
+	e := From(ctx)
+	m := vars(e)
+	secrets := map[string]bool{}
+	for key := range m {
+		if isSecret(e, key) {
+			secrets[key] = true
+		}
+	}
+	return &secretEnv{Env: ReadOnly(&Map{env: m}), names: secrets}
+}
+
+// Delta lists the keys that differ between two Envs, in sorted order.
+type Delta struct {
+	Added, Removed, Changed []string
+}
+
+// IsEmpty reports whether there are no differences.
+func (d Delta) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func (d Delta) String() string {
+	return fmt.Sprintf("added %v, removed %v, changed %v", d.Added, d.Removed, d.Changed)
+}
+
+// Diff reports the keys added, removed and changed going from a to b.
+func Diff(a, b Env) Delta {
+	var d Delta
+	av, bv := vars(a), vars(b)
+	for key, value := range bv {
+		if old, has := av[key]; !has {
+			d.Added = append(d.Added, key)
+		} else if old != value {
+			d.Changed = append(d.Changed, key)
+		}
+	}
+	for key := range av {
+		if _, has := bv[key]; !has {
+			d.Removed = append(d.Removed, key)
+		}
+	}
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Strings(d.Changed)
+	return d
+}
+
+// Restore makes the context Env match snapshot, setting and unsetting only
+// the variables that differ.
+func Restore(ctx context.Context, snapshot Env) error {
+	e := From(ctx)
+	want := vars(snapshot)
+	for key := range vars(e) {
+		if _, has := want[key]; !has {
+			if err := e.Unsetenv(key); err != nil {
+				return err
+			}
+		}
+	}
+	for key, value := range want {
+		if old, has := e.LookupEnv(key); !has || old != value {
+			if err := e.Setenv(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RestoreOnCleanup snapshots the context Env and restores it when tb's test
+// finishes, so that tests modifying the environment don't leak into others.
+// Tests using it on the process environment must not run in parallel.
+func RestoreOnCleanup(ctx context.Context, tb testing.TB) {
+	tb.Helper()
+	snapshot := Snapshot(ctx)
+	tb.Cleanup(func() {
+		if err := Restore(ctx, snapshot); err != nil {
+			tb.Errorf("env: restoring environment: %v", err)
+		}
+	})
+}
+
+// vars copies the variables of e, reading values with LookupEnv.
+func vars(e Env) map[string]string {
+	result := map[string]string{}
+	for _, kv := range e.Environ() {
+		key, value, _ := strings.Cut(kv, "=")
+		if v, has := e.LookupEnv(key); has {
+			value = v
+		}
+		result[key] = value
+	}
+	return result
+}
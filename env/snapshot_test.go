@@ -0,0 +1,52 @@
+package env
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	m := NewMap(map[string]string{"A": "1", "B": "2", "C": "3"})
+	ctx := Onto(context.Background(), WithSecrets(m, "C"))
+	snapshot := Snapshot(ctx)
+	assert.ErrorIs(t, snapshot.Setenv("A", "x"), ErrReadOnly)
+	assert.Equal(t, "3", getenv(snapshot, "C"))
+	assert.True(t, isSecret(snapshot, "C"))
+	assert.False(t, isSecret(snapshot, "A"))
+	assert.ElementsMatch(t, []string{"A=1", "B=2", "C=" + Redacted}, snapshot.Environ())
+
+	require.NoError(t, Setenv(ctx, "A", "changed"))
+	require.NoError(t, Unsetenv(ctx, "B"))
+	require.NoError(t, Setenv(ctx, "D", "new"))
+	require.NoError(t, Setenv(ctx, "C", "secret"))
+	assert.Equal(t, "1", getenv(snapshot, "A"))
+
+	d := Diff(snapshot, From(ctx))
+	assert.Equal(t, Delta{Added: []string{"D"}, Removed: []string{"B"}, Changed: []string{"A", "C"}}, d)
+	assert.Equal(t, "added [D], removed [B], changed [A C]", d.String())
+	assert.False(t, d.IsEmpty())
+
+	require.NoError(t, Restore(ctx, snapshot))
+	assert.True(t, Diff(snapshot, m).IsEmpty())
+	assert.ElementsMatch(t, []string{"A=1", "B=2", "C=3"}, m.Environ())
+
+	assert.ErrorIs(t, Restore(Onto(ctx, snapshot), NewMap(nil)), ErrReadOnly)
+}
+
+func TestRestoreOnCleanup(t *testing.T) {
+	// Do not parallelise.
+
+	ctx := context.Background()
+	before := Snapshot(ctx)
+	t.Run("modify", func(t *testing.T) {
+		RestoreOnCleanup(ctx, t)
+		require.NoError(t, Setenv(ctx, "ENV_SNAPSHOT_TEST", "1"))
+		require.NoError(t, Setenv(ctx, "PATH", "/nowhere"))
+	})
+	assert.True(t, Diff(before, From(ctx)).IsEmpty())
+}
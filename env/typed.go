@@ -0,0 +1,164 @@
+package env
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotSet is wrapped by errors returned from the Required* functions when
+// the variable is not set.
+var ErrNotSet = errors.New("not set")
+
+// VarError records an error reading an environment variable.
+type VarError struct {
+	Key string
+	Err error
+}
+
+func (e *VarError) Error() string {
+	return "env: " + e.Key + ": " + e.Err.Error()
+}
+
+func (e *VarError) Unwrap() error {
+	return e.Err
+}
+
+// The typed accessors below read variables from the context Env. The plain
+// forms return def if the variable is not set. The Required* forms return an
+// error wrapping ErrNotSet instead. Both return a *VarError if the variable is
+// set but cannot be parsed.
+
+func String(ctx context.Context, key, def string) string {
+	if value, has := LookupEnv(ctx, key); has {
+		return value
+	}
+	return def
+}
+
+func RequiredString(ctx context.Context, key string) (string, error) {
+	return required(ctx, key, func(s string) (string, error) { return s, nil })
+}
+
+func Int(ctx context.Context, key string, def int) (int, error) {
+	return optional(ctx, key, def, strconv.Atoi)
+}
+
+func RequiredInt(ctx context.Context, key string) (int, error) {
+	return required(ctx, key, strconv.Atoi)
+}
+
+func Bool(ctx context.Context, key string, def bool) (bool, error) {
+	return optional(ctx, key, def, strconv.ParseBool)
+}
+
+func RequiredBool(ctx context.Context, key string) (bool, error) {
+	return required(ctx, key, strconv.ParseBool)
+}
+
+func Duration(ctx context.Context, key string, def time.Duration) (time.Duration, error) {
+	return optional(ctx, key, def, time.ParseDuration)
+}
+
+func RequiredDuration(ctx context.Context, key string) (time.Duration, error) {
+	return required(ctx, key, time.ParseDuration)
+}
+
+func Float(ctx context.Context, key string, def float64) (float64, error) {
+	return optional(ctx, key, def, parseFloat)
+}
+
+func RequiredFloat(ctx context.Context, key string) (float64, error) {
+	return required(ctx, key, parseFloat)
+}
+
+func URL(ctx context.Context, key string, def *url.URL) (*url.URL, error) {
+	return optional(ctx, key, def, url.Parse)
+}
+
+func RequiredURL(ctx context.Context, key string) (*url.URL, error) {
+	return required(ctx, key, url.Parse)
+}
+
+// Strings splits the variable on sep, trimming space around each element. An
+// empty value yields an empty slice.
+func Strings(ctx context.Context, key, sep string, def []string) ([]string, error) {
+	return optional(ctx, key, def, parseStrings(sep))
+}
+
+func RequiredStrings(ctx context.Context, key, sep string) ([]string, error) {
+	return required(ctx, key, parseStrings(sep))
+}
+
+// StringMap splits the variable on sep into entries and each entry on kvSep
+// into a key and value, e.g. "a=1,b=2" with sep "," and kvSep "=".
+func StringMap(ctx context.Context, key, sep, kvSep string, def map[string]string) (map[string]string, error) {
+	return optional(ctx, key, def, parseStringMap(sep, kvSep))
+}
+
+func RequiredStringMap(ctx context.Context, key, sep, kvSep string) (map[string]string, error) {
+	return required(ctx, key, parseStringMap(sep, kvSep))
+}
+
+func optional[T any](ctx context.Context, key string, def T, parse func(string) (T, error)) (T, error) {
+	value, has := LookupEnv(ctx, key)
+	if !has {
+		return def, nil
+	}
+	result, err := parse(value)
+	if err != nil {
+		return def, &VarError{Key: key, Err: err}
+	}
+	return result, nil
+}
+
+func required[T any](ctx context.Context, key string, parse func(string) (T, error)) (T, error) {
+	var zero T
+	value, has := LookupEnv(ctx, key)
+	if !has {
+		return zero, &VarError{Key: key, Err: ErrNotSet}
+	}
+	result, err := parse(value)
+	if err != nil {
+		return zero, &VarError{Key: key, Err: err}
+	}
+	return result, nil
+}
+
+func parseFloat(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
+
+func parseStrings(sep string) func(string) ([]string, error) {
+	return func(s string) ([]string, error) {
+		if s == "" {
+			return []string{}, nil
+		}
+		parts := strings.Split(s, sep)
+		for i, part := range parts {
+			parts[i] = strings.TrimSpace(part)
+		}
+		return parts, nil
+	}
+}
+
+func parseStringMap(sep, kvSep string) func(string) (map[string]string, error) {
+	return func(s string) (map[string]string, error) {
+		result := map[string]string{}
+		if s == "" {
+			return result, nil
+		}
+		for _, entry := range strings.Split(s, sep) {
+			k, v, ok := strings.Cut(entry, kvSep)
+			if !ok {
+				return nil, fmt.Errorf("entry %q has no %q separator", entry, kvSep)
+			}
+			result[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+		return result, nil
+	}
+}
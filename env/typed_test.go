@@ -0,0 +1,105 @@
+package env
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTyped(t *testing.T) {
+	t.Parallel()
+
+	ctx := Onto(context.Background(), NewMap(map[string]string{
+		"NAME":     "svc",
+		"PORT":     "8080",
+		"DEBUG":    "true",
+		"TIMEOUT":  "5s",
+		"RATIO":    "0.5",
+		"ENDPOINT": "https://example.com/api",
+		"HOSTS":    "a, b,c",
+		"LABELS":   "team=core; tier = 1",
+		"EMPTY":    "",
+	}))
+
+	assert.Equal(t, "svc", String(ctx, "NAME", "default"))
+	assert.Equal(t, "default", String(ctx, "MISSING", "default"))
+	name, err := RequiredString(ctx, "NAME")
+	require.NoError(t, err)
+	assert.Equal(t, "svc", name)
+
+	port, err := Int(ctx, "PORT", 80)
+	require.NoError(t, err)
+	assert.Equal(t, 8080, port)
+	port, err = Int(ctx, "MISSING", 80)
+	require.NoError(t, err)
+	assert.Equal(t, 80, port)
+
+	debug, err := RequiredBool(ctx, "DEBUG")
+	require.NoError(t, err)
+	assert.True(t, debug)
+
+	timeout, err := Duration(ctx, "TIMEOUT", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, timeout)
+
+	ratio, err := RequiredFloat(ctx, "RATIO")
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, ratio)
+
+	endpoint, err := RequiredURL(ctx, "ENDPOINT")
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", endpoint.Host)
+	def := &url.URL{Scheme: "http", Host: "localhost"}
+	endpoint, err = URL(ctx, "MISSING", def)
+	require.NoError(t, err)
+	assert.Same(t, def, endpoint)
+
+	hosts, err := Strings(ctx, "HOSTS", ",", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, hosts)
+	hosts, err = RequiredStrings(ctx, "EMPTY", ",")
+	require.NoError(t, err)
+	assert.Empty(t, hosts)
+
+	labels, err := StringMap(ctx, "LABELS", ";", "=", nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "core", "tier": "1"}, labels)
+	labels, err = RequiredStringMap(ctx, "EMPTY", ";", "=")
+	require.NoError(t, err)
+	assert.Empty(t, labels)
+}
+
+func TestTypedErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := Onto(context.Background(), NewMap(map[string]string{
+		"PORT":   "http",
+		"DEBUG":  "maybe",
+		"LABELS": "team",
+	}))
+
+	port, err := Int(ctx, "PORT", 80)
+	assert.Equal(t, 80, port)
+	var varErr *VarError
+	if assert.ErrorAs(t, err, &varErr) {
+		assert.Equal(t, "PORT", varErr.Key)
+	}
+	assert.EqualError(t, err, `env: PORT: strconv.Atoi: parsing "http": invalid syntax`)
+
+	_, err = Bool(ctx, "DEBUG", false)
+	assert.Error(t, err)
+
+	_, err = RequiredDuration(ctx, "TIMEOUT")
+	assert.ErrorIs(t, err, ErrNotSet)
+	assert.EqualError(t, err, "env: TIMEOUT: not set")
+	_, err = RequiredInt(ctx, "PORT")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotSet)
+
+	_, err = StringMap(ctx, "LABELS", ",", "=", nil)
+	assert.EqualError(t, err, `env: LABELS: entry "team" has no "=" separator`)
+}
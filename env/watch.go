@@ -0,0 +1,180 @@
+package env
+
+import (
+	"context"
+	"sync"
+)
+
+// Change describes a change to an environment variable.
+type Change struct {
+	Key      string
+	Old, New string
+	// WasSet and IsSet report whether the variable was set before and after
+	// the change, distinguishing unset variables from empty ones.
+	WasSet, IsSet bool
+}
+
+// Observable wraps an Env so that changes made through it can be watched.
+// Changes made directly to the underlying Env are not observed.
+type Observable struct {
+	env Env
+
+	mux      sync.Mutex
+	watchers map[*watcher]bool
+}
+
+var _ Env = &Observable{}
+
+// NewObservable creates an Observable wrapping e.
+func NewObservable(e Env) *Observable {
+	return &Observable{env: e, watchers: map[*watcher]bool{}}
+}
+
+// Watch returns a channel that receives a Change each time one of keys is
+// set, changed or unset, or any variable if no keys are given. Setting a
+// variable to its current value is not a change. Changes are delivered in
+// order and never block writers. The channel is closed once ctx is done.
+func (o *Observable) Watch(ctx context.Context, keys ...string) <-chan Change {
+	w := &watcher{
+		signal: make(chan struct{}, 1),
+		c:      make(chan Change),
+	}
+	if len(keys) > 0 {
+		w.keys = make(map[string]bool, len(keys))
+		for _, key := range keys {
+			w.keys[key] = true
+		}
+	}
+	o.mux.Lock()
+	o.watchers[w] = true
+	o.mux.Unlock()
+	go func() {
+		defer func() {
+			o.mux.Lock()
+			delete(o.watchers, w)
+			o.mux.Unlock()
+		}()
+		w.run(ctx)
+	}()
+	return w.c
+}
+
+func (o *Observable) Clearenv() {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	before := vars(o.env)
+	o.env.Clearenv()
+	for key, value := range before {
+		o.notifyLocked(Change{Key: key, Old: value, WasSet: true})
+	}
+}
+
+func (o *Observable) Environ() []string {
+	return o.env.Environ()
+}
+
+func (o *Observable) LookupEnv(key string) (string, bool) {
+	return o.env.LookupEnv(key)
+}
+
+func (o *Observable) Setenv(key, value string) error {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	old, wasSet := o.env.LookupEnv(key)
+	if err := o.env.Setenv(key, value); err != nil {
+		return err
+	}
+	o.notifyLocked(Change{Key: key, Old: old, New: value, WasSet: wasSet, IsSet: true})
+	return nil
+}
+
+func (o *Observable) Unsetenv(key string) error {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	old, wasSet := o.env.LookupEnv(key)
+	if err := o.env.Unsetenv(key); err != nil {
+		return err
+	}
+	o.notifyLocked(Change{Key: key, Old: old, WasSet: wasSet})
+	return nil
+}
+
+// replace makes the wrapped Env hold exactly m, notifying the differences.
+func (o *Observable) replace(m map[string]string) error {
+	o.mux.Lock()
+	defer o.mux.Unlock()
+	for key, old := range vars(o.env) {
+		if _, has := m[key]; !has {
+			if err := o.env.Unsetenv(key); err != nil {
+				return err
+			}
+			o.notifyLocked(Change{Key: key, Old: old, WasSet: true})
+		}
+	}
+	for key, value := range m {
+		old, wasSet := o.env.LookupEnv(key)
+		if err := o.env.Setenv(key, value); err != nil {
+			return err
+		}
+		o.notifyLocked(Change{Key: key, Old: old, New: value, WasSet: wasSet, IsSet: true})
+	}
+	return nil
+}
+
+func (o *Observable) notifyLocked(c Change) {
+	if c.Old == c.New && c.WasSet == c.IsSet {
+		return
+	}
+	for w := range o.watchers {
+		if w.keys == nil || w.keys[c.Key] {
+			w.push(c)
+		}
+	}
+}
+
+// watcher queues changes for a subscriber so that writers never block.
+type watcher struct {
+	keys   map[string]bool
+	signal chan struct{}
+	c      chan Change
+
+	mux   sync.Mutex
+	queue []Change
+}
+
+func (w *watcher) push(c Change) {
+	w.mux.Lock()
+	w.queue = append(w.queue, c)
+	w.mux.Unlock()
+	select {
+	case w.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (w *watcher) run(ctx context.Context) {
+	defer close(w.c)
+	for {
+		w.mux.Lock()
+		queue := w.queue
+		w.queue = nil
+		w.mux.Unlock()
+		for _, c := range queue {
+			select {
+			case w.c <- c:
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case <-w.signal:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// IsSecret reports whether key is secret in the wrapped Env.
+func (o *Observable) IsSecret(key string) bool {
+	return isSecret(o.env, key)
+}
@@ -0,0 +1,118 @@
+package env
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anz-bank/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObservable(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	o := NewObservable(NewMap(map[string]string{"A": "1"}))
+	all := o.Watch(ctx)
+	onlyB := o.Watch(ctx, "B")
+
+	require.NoError(t, o.Setenv("A", "1"))
+	require.NoError(t, o.Setenv("A", "2"))
+	require.NoError(t, o.Setenv("B", ""))
+	require.NoError(t, o.Unsetenv("A"))
+	require.NoError(t, o.Unsetenv("A"))
+	o.Clearenv()
+
+	assert.Equal(t, Change{Key: "A", Old: "1", New: "2", WasSet: true, IsSet: true}, <-all)
+	assert.Equal(t, Change{Key: "B", IsSet: true}, <-all)
+	assert.Equal(t, Change{Key: "A", Old: "2", WasSet: true}, <-all)
+	assert.Equal(t, Change{Key: "B", WasSet: true}, <-all)
+	assert.Equal(t, Change{Key: "B", IsSet: true}, <-onlyB)
+	assert.Equal(t, Change{Key: "B", WasSet: true}, <-onlyB)
+
+	cancel()
+	for range all {
+	}
+	for range onlyB {
+	}
+	assert.Eventually(t, func() bool {
+		o.mux.Lock()
+		defer o.mux.Unlock()
+		return len(o.watchers) == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestObservableSecrets(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	o := NewObservable(WithSecrets(NewMap(map[string]string{"DB_PASSWORD": "a"}), "*_PASSWORD"))
+	c := o.Watch(ctx)
+
+	require.NoError(t, o.replace(map[string]string{"OTHER": "x"}))
+	assert.Equal(t, Change{Key: "DB_PASSWORD", Old: "a", WasSet: true}, <-c)
+	assert.Equal(t, Change{Key: "OTHER", New: "x", IsSet: true}, <-c)
+
+	require.NoError(t, o.Setenv("DB_PASSWORD", "b"))
+	assert.Equal(t, Change{Key: "DB_PASSWORD", New: "b", IsSet: true}, <-c)
+	require.NoError(t, o.Unsetenv("OTHER"))
+	assert.Equal(t, Change{Key: "OTHER", Old: "x", WasSet: true}, <-c)
+	o.Clearenv()
+	assert.Equal(t, Change{Key: "DB_PASSWORD", Old: "b", WasSet: true}, <-c)
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"app.env", "app.json"} {
+		name := name
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			f := clock.NewFake(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+			ctx, cancel := context.WithCancel(clock.Onto(context.Background(), f))
+			defer cancel()
+
+			filename := filepath.Join(t.TempDir(), name)
+			write := func(dotenv, json string, mtime time.Time) {
+				content := dotenv
+				if filepath.Ext(name) == ".json" {
+					content = json
+				}
+				require.NoError(t, os.WriteFile(filename, []byte(content), 0o600))
+				require.NoError(t, os.Chtimes(filename, mtime, mtime))
+			}
+			write("LEVEL=info\nKEEP=x\n", `{"LEVEL":"info","KEEP":"x"}`, time.Unix(1, 0))
+
+			file, err := LoadFile(ctx, filename, time.Second)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"LEVEL=info", "KEEP=x"}, file.Environ())
+			changes := file.Watch(ctx)
+
+			write("LEVEL=debug\nKEEP=x\n", `{"LEVEL":"debug","KEEP":"x"}`, time.Unix(2, 0))
+			require.NoError(t, f.WaitForWaiters(ctx, 1))
+			f.Advance(time.Second)
+			assert.Equal(t, Change{Key: "LEVEL", Old: "info", New: "debug", WasSet: true, IsSet: true}, <-changes)
+
+			// Failed reloads keep the previous values.
+			write("LEVEL='", `{"LEVEL":`, time.Unix(3, 0))
+			assert.Error(t, file.Reload())
+			assert.Error(t, file.Err())
+			assert.Equal(t, "debug", getenv(file, "LEVEL"))
+
+			write("KEEP=x\n", `{"KEEP":"x"}`, time.Unix(4, 0))
+			require.NoError(t, f.WaitForWaiters(ctx, 1))
+			f.Advance(time.Second)
+			assert.Equal(t, Change{Key: "LEVEL", Old: "debug", WasSet: true}, <-changes)
+			assert.NoError(t, file.Err())
+		})
+	}
+
+	_, err := LoadFile(context.Background(), filepath.Join(t.TempDir(), "missing.env"), time.Second)
+	assert.Error(t, err)
+}
@@ -0,0 +1,36 @@
+package health
+
+import (
+	"context"
+	"sync"
+
+	"github.com/anz-bank/pkg/ratelimit"
+)
+
+// Throttle returns a ReadyProvider that consults p no more often than l
+// allows, and otherwise reports the result of the previous check. Use it to
+// protect expensive readiness checks from frequent probes. The limiter reads
+// the time from the clock in ctx.
+//
+//	state.SetReadyProvider(health.Throttle(ctx, checker, ratelimit.NewTokenBucket(10*time.Second, 1)))
+func Throttle(ctx context.Context, p ReadyProvider, l ratelimit.Limiter) ReadyProvider {
+	return &throttledReadyProvider{ctx: ctx, provider: p, limiter: l}
+}
+
+type throttledReadyProvider struct {
+	ctx      context.Context
+	provider ReadyProvider
+	limiter  ratelimit.Limiter
+
+	mux   sync.Mutex
+	ready bool
+}
+
+func (t *throttledReadyProvider) IsReady() bool {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+	if t.limiter.Allow(t.ctx) {
+		t.ready = t.provider.IsReady()
+	}
+	return t.ready
+}
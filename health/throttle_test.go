@@ -0,0 +1,42 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anz-bank/pkg/clock"
+	"github.com/anz-bank/pkg/ratelimit"
+	"github.com/stretchr/testify/require"
+)
+
+type countingReadyProvider struct {
+	readiness
+	calls int
+}
+
+func (c *countingReadyProvider) IsReady() bool {
+	c.calls++
+	return c.readiness.IsReady()
+}
+
+func TestThrottle(t *testing.T) {
+	f := clock.NewFake(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	ctx := clock.Onto(context.Background(), f)
+	p := &countingReadyProvider{}
+	p.SetReady(true)
+
+	s, err := NewState()
+	require.NoError(t, err)
+	s.SetReadyProvider(Throttle(ctx, p, ratelimit.NewTokenBucket(10*time.Second, 1)))
+
+	require.True(t, s.IsReady())
+	p.SetReady(false)
+	require.True(t, s.IsReady())
+	require.Equal(t, 1, p.calls)
+
+	f.Advance(10 * time.Second)
+	require.False(t, s.IsReady())
+	require.False(t, s.IsReady())
+	require.Equal(t, 2, p.calls)
+}
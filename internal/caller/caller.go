@@ -0,0 +1,33 @@
+// Package caller locates the code calling into a package, for decorators that
+// record their call sites.
+package caller
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Dir returns the directory of the source file that calls it. Packages use it
+// to initialise the dir passed to Outside.
+func Dir() string {
+	_, file, _, _ := runtime.Caller(1)
+	return filepath.Dir(file)
+}
+
+// Outside returns the location of the innermost caller whose source file is
+// not in dir. Test files in dir count as outside, so that a package's own
+// tests are reported as callers.
+func Outside(dir string) (string, int) {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != dir || strings.HasSuffix(frame.File, "_test.go") {
+			return frame.File, frame.Line
+		}
+		if !more {
+			return "", 0
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package caller
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutside(t *testing.T) {
+	t.Parallel()
+
+	_, file, line, _ := runtime.Caller(0)
+	assert.Equal(t, filepath.Dir(file), Dir())
+
+	// Test files count as callers even though they are in dir.
+	f, l := Outside(Dir())
+	assert.Equal(t, file, f)
+	assert.Equal(t, line+4, l)
+
+	f, _ = Outside(filepath.Join(runtime.GOROOT(), "src", "testing"))
+	assert.Equal(t, file, f)
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/anz-bank/pkg/env"
 	"github.com/arr-ai/frozen"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -359,3 +360,18 @@ func TestStandardLoggerWithFields(t *testing.T) {
 	require.True(t, strings.Contains(buffer.String(), "string=this is an unnecessarily long sentence"))
 	require.True(t, strings.Contains(buffer.String(), "standardLogger_test.go")) // Log caller
 }
+
+func TestFormatRedactsSecrets(t *testing.T) {
+	t.Parallel()
+
+	entry := &LogEntry{
+		Message: testMessage,
+		Data:    frozen.NewMap(frozen.KV[any, any]("password", env.NewSecret("hunter2"))),
+	}
+	for _, format := range []Config{NewStandardFormat(), NewJSONFormat()} {
+		out, err := format.(Formatter).Format(entry)
+		require.NoError(t, err)
+		assert.NotContains(t, out, "hunter2")
+		assert.Contains(t, out, env.Redacted)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/anz-bank/pkg/clock"
 	"github.com/anz-bank/pkg/logging/codelinks"
 	"github.com/arr-ai/frozen"
 	"github.com/rs/zerolog"
@@ -169,6 +170,16 @@ func (l Logger) WithTimeDiff(key string, start time.Time) *Logger {
 	return &l
 }
 
+// WithStopwatch adds a time diff field to the logger that reports the elapsed
+// time of a clock.Stopwatch
+func (l Logger) WithStopwatch(key string, sw *clock.Stopwatch) *Logger {
+	l.timeDiffs = append(l.timeDiffs, &timeDiff{
+		key:       key,
+		stopwatch: sw,
+	})
+	return &l
+}
+
 // WithOutput creates a copy of the logger with a new writer to write logs to
 func (l Logger) WithOutput(out io.Writer) *Logger {
 	l.internal = l.internal.Output(out)
@@ -237,12 +248,17 @@ func (l *Logger) withInfo(event *zerolog.Event, depth int) *zerolog.Event {
 		event = event.Str("source_code", l.linker.Link(file, line))
 	}
 	for _, td := range l.timeDiffs {
-		event = event.TimeDiff(td.key, time.Now(), td.start)
+		if td.stopwatch != nil {
+			event = event.Dur(td.key, td.stopwatch.Elapsed())
+		} else {
+			event = event.TimeDiff(td.key, time.Now(), td.start)
+		}
 	}
 	return event
 }
 
 type timeDiff struct {
-	key   string
-	start time.Time
+	key       string
+	start     time.Time
+	stopwatch *clock.Stopwatch
 }
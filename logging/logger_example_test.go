@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/anz-bank/pkg/clock"
 	"github.com/anz-bank/pkg/logging"
 	"github.com/rs/zerolog"
 )
@@ -72,6 +73,16 @@ func ExampleLogger_WithTimeDiff() {
 	logger.Info().Msg("Hello World")
 }
 
+func ExampleLogger_WithStopwatch() {
+	f := clock.NewFake(time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC))
+	sw := clock.StartStopwatch(clock.Onto(context.Background(), f))
+	logger := logging.New(os.Stdout).WithStopwatch("elapsed", sw)
+
+	f.Advance(1500 * time.Millisecond)
+	logger.Info().Msg("Hello World")
+	// Output: {"level":"info","elapsed":1500,"message":"Hello World"}
+}
+
 func ExampleInfo() {
 	// Standard log calls will look like this.
 	// MAKE SURE your context has a logger in it, otherwise it will panic
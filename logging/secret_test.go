@@ -0,0 +1,23 @@
+package logging_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/anz-bank/pkg/env"
+	"github.com/anz-bank/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_RedactsSecrets(t *testing.T) {
+	t.Parallel()
+	buf := bytes.Buffer{}
+	logger := logging.New(&buf)
+	secret := env.NewSecret("hunter2")
+	logger.Info().
+		Interface("interface", secret).
+		Stringer("stringer", secret).
+		Msgf("password is %v", secret)
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.Contains(t, buf.String(), `"interface":"***","stringer":"***","message":"password is ***"`)
+}
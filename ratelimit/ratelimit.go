@@ -0,0 +1,223 @@
+// Package ratelimit provides token-bucket and leaky-bucket rate limiters.
+// Limiters read the time from clock.From(ctx), so limits can be verified
+// deterministically with clock.Fake or clock.TimeTravel.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/anz-bank/pkg/clock"
+)
+
+// ErrLimitExceeded is returned by Wait when an event can never be permitted,
+// or cannot be permitted before the context deadline.
+var ErrLimitExceeded = errors.New("ratelimit: limit exceeded")
+
+// Limiter controls how frequently events may happen.
+type Limiter interface {
+	// Allow reports whether an event may happen now, consuming capacity if
+	// it may.
+	Allow(ctx context.Context) bool
+	// Reserve reserves capacity for an event and reports when it may happen.
+	Reserve(ctx context.Context) *Reservation
+	// Wait blocks until an event may happen or ctx is done.
+	Wait(ctx context.Context) error
+}
+
+// Reservation holds capacity reserved by a Limiter for a future event.
+type Reservation struct {
+	ok     bool
+	at     time.Time
+	cancel func(at time.Time)
+}
+
+// OK reports whether the event can ever be permitted. If not, the other
+// methods have no meaning.
+func (r *Reservation) OK() bool {
+	return r.ok
+}
+
+// Time returns when the event may happen.
+func (r *Reservation) Time() time.Time {
+	return r.at
+}
+
+// Delay returns how long to wait on the context clock before the event may
+// happen.
+func (r *Reservation) Delay(ctx context.Context) time.Duration {
+	if d := clock.Until(ctx, r.at); d > 0 {
+		return d
+	}
+	return 0
+}
+
+// Cancel returns the reserved capacity to the Limiter, as far as possible.
+// Call it when the event will not happen after all.
+func (r *Reservation) Cancel() {
+	if r.ok && r.cancel != nil {
+		r.cancel(r.at)
+		r.cancel = nil
+	}
+}
+
+// wait implements Limiter.Wait in terms of Reserve.
+func wait(ctx context.Context, l Limiter) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	r := l.Reserve(ctx)
+	if !r.OK() {
+		return ErrLimitExceeded
+	}
+	delay := r.Delay(ctx)
+	if delay <= 0 {
+		return nil
+	}
+	if deadline, has := ctx.Deadline(); has && clock.Until(ctx, deadline) < delay {
+		r.Cancel()
+		return ErrLimitExceeded
+	}
+	if err := clock.SleepContext(ctx, delay); err != nil {
+		r.Cancel()
+		return err
+	}
+	return nil
+}
+
+// TokenBucket is a Limiter that refills at a constant rate up to a maximum
+// burst size. Each event consumes one token.
+type TokenBucket struct {
+	interval time.Duration
+	burst    int
+
+	mux    sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+var _ Limiter = &TokenBucket{}
+
+// NewTokenBucket creates a full TokenBucket that gains one token every
+// interval, holding at most burst tokens. It panics if interval or burst is
+// not positive.
+func NewTokenBucket(interval time.Duration, burst int) *TokenBucket {
+	if interval <= 0 || burst <= 0 {
+		panic("ratelimit.NewTokenBucket: non-positive interval or burst")
+	}
+	return &TokenBucket{interval: interval, burst: burst, tokens: float64(burst)}
+}
+
+// advance refills tokens up to now. The caller must hold b.mux.
+func (b *TokenBucket) advance(now time.Time) {
+	if !b.last.IsZero() && now.After(b.last) {
+		b.tokens += float64(now.Sub(b.last)) / float64(b.interval)
+		if b.tokens > float64(b.burst) {
+			b.tokens = float64(b.burst)
+		}
+	}
+	if b.last.IsZero() || now.After(b.last) {
+		b.last = now
+	}
+}
+
+func (b *TokenBucket) Allow(ctx context.Context) bool {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.advance(clock.Now(ctx))
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *TokenBucket) Reserve(ctx context.Context) *Reservation {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	now := clock.Now(ctx)
+	b.advance(now)
+	b.tokens--
+	at := now
+	if b.tokens < 0 {
+		at = now.Add(time.Duration(-b.tokens * float64(b.interval)))
+	}
+	return &Reservation{ok: true, at: at, cancel: b.refund}
+}
+
+func (b *TokenBucket) refund(time.Time) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	b.tokens++
+	if b.tokens > float64(b.burst) {
+		b.tokens = float64(b.burst)
+	}
+}
+
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	return wait(ctx, b)
+}
+
+// LeakyBucket is a Limiter that spaces events evenly, one every interval.
+// Up to capacity events may be queued waiting for their turn; further events
+// are refused.
+type LeakyBucket struct {
+	interval time.Duration
+	capacity int
+
+	mux  sync.Mutex
+	next time.Time
+}
+
+var _ Limiter = &LeakyBucket{}
+
+// NewLeakyBucket creates an empty LeakyBucket that lets one event through
+// every interval and queues at most capacity events. It panics if interval is
+// not positive or capacity is negative.
+func NewLeakyBucket(interval time.Duration, capacity int) *LeakyBucket {
+	if interval <= 0 || capacity < 0 {
+		panic("ratelimit.NewLeakyBucket: non-positive interval or negative capacity")
+	}
+	return &LeakyBucket{interval: interval, capacity: capacity}
+}
+
+func (b *LeakyBucket) Allow(ctx context.Context) bool {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	now := clock.Now(ctx)
+	if b.next.After(now) {
+		return false
+	}
+	b.next = now.Add(b.interval)
+	return true
+}
+
+func (b *LeakyBucket) Reserve(ctx context.Context) *Reservation {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	now := clock.Now(ctx)
+	at := now
+	if b.next.After(now) {
+		at = b.next
+	}
+	if at.Sub(now) > time.Duration(b.capacity)*b.interval {
+		return &Reservation{}
+	}
+	b.next = at.Add(b.interval)
+	return &Reservation{ok: true, at: at, cancel: b.refund}
+}
+
+// refund gives back the slot at at if it is the most recently reserved one.
+func (b *LeakyBucket) refund(at time.Time) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if b.next.Equal(at.Add(b.interval)) {
+		b.next = at
+	}
+}
+
+func (b *LeakyBucket) Wait(ctx context.Context) error {
+	return wait(ctx, b)
+}
@@ -0,0 +1,158 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anz-bank/pkg/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var epoch = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func fake() (*clock.Fake, context.Context) {
+	f := clock.NewFake(epoch)
+	return f, clock.Onto(context.Background(), f)
+}
+
+func TestTokenBucketAllow(t *testing.T) {
+	t.Parallel()
+
+	f, ctx := fake()
+	b := NewTokenBucket(time.Second, 3)
+	for i := 0; i < 3; i++ {
+		assert.True(t, b.Allow(ctx), i)
+	}
+	assert.False(t, b.Allow(ctx))
+	f.Advance(999 * time.Millisecond)
+	assert.False(t, b.Allow(ctx))
+	f.Advance(time.Millisecond)
+	assert.True(t, b.Allow(ctx))
+	assert.False(t, b.Allow(ctx))
+
+	// The bucket never holds more than the burst size.
+	f.Advance(time.Hour)
+	for i := 0; i < 3; i++ {
+		assert.True(t, b.Allow(ctx), i)
+	}
+	assert.False(t, b.Allow(ctx))
+
+	assert.Panics(t, func() { NewTokenBucket(0, 1) })
+	assert.Panics(t, func() { NewTokenBucket(time.Second, 0) })
+}
+
+func TestTokenBucketReserve(t *testing.T) {
+	t.Parallel()
+
+	f, ctx := fake()
+	b := NewTokenBucket(time.Second, 1)
+	r := b.Reserve(ctx)
+	assert.True(t, r.OK())
+	assert.Equal(t, epoch, r.Time())
+	assert.Zero(t, r.Delay(ctx))
+
+	r = b.Reserve(ctx)
+	assert.Equal(t, epoch.Add(time.Second), r.Time())
+	assert.Equal(t, time.Second, r.Delay(ctx))
+	r2 := b.Reserve(ctx)
+	assert.Equal(t, epoch.Add(2*time.Second), r2.Time())
+
+	r2.Cancel()
+	r.Cancel()
+	f.Advance(time.Second)
+	assert.True(t, b.Allow(ctx))
+}
+
+func TestTokenBucketWait(t *testing.T) {
+	t.Parallel()
+
+	tt := clock.NewTimeTravel(0)
+	defer tt.Close()
+	ctx := clock.Onto(context.Background(), tt)
+	t0 := clock.Now(ctx)
+
+	b := NewTokenBucket(time.Minute, 1)
+	for i := 0; i < 11; i++ {
+		require.NoError(t, b.Wait(ctx))
+	}
+	assert.GreaterOrEqual(t, clock.Since(ctx, t0), 10*time.Minute)
+}
+
+func TestWaitDeadline(t *testing.T) {
+	t.Parallel()
+
+	_, ctx := fake()
+	b := NewTokenBucket(time.Minute, 1)
+	require.True(t, b.Allow(ctx))
+
+	tctx, cancel := clock.WithTimeout(ctx, time.Second)
+	defer cancel()
+	assert.Equal(t, ErrLimitExceeded, b.Wait(tctx))
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Equal(t, context.Canceled, b.Wait(cctx))
+}
+
+func TestWaitWallDeadline(t *testing.T) {
+	t.Parallel()
+
+	// The context clock runs well ahead of the wall-clock deadline, but a
+	// token is available now.
+	f := clock.NewFake(time.Now().Add(24 * time.Hour))
+	ctx, cancel := context.WithTimeout(clock.Onto(context.Background(), f), time.Hour)
+	defer cancel()
+	assert.NoError(t, NewTokenBucket(time.Minute, 1).Wait(ctx))
+}
+
+func TestWaitCancelled(t *testing.T) {
+	t.Parallel()
+
+	f, ctx := fake()
+	b := NewLeakyBucket(time.Minute, 1)
+	require.True(t, b.Allow(ctx))
+
+	cctx, cancel := context.WithCancel(ctx)
+	errs := make(chan error)
+	go func() { errs <- b.Wait(cctx) }()
+	require.NoError(t, f.WaitForWaiters(ctx, 1))
+	cancel()
+	assert.Equal(t, context.Canceled, <-errs)
+
+	// The cancelled reservation was returned.
+	assert.True(t, b.Reserve(ctx).OK())
+}
+
+func TestLeakyBucket(t *testing.T) {
+	t.Parallel()
+
+	f, ctx := fake()
+	b := NewLeakyBucket(time.Second, 2)
+	assert.True(t, b.Allow(ctx))
+	assert.False(t, b.Allow(ctx))
+
+	r1 := b.Reserve(ctx)
+	r2 := b.Reserve(ctx)
+	r3 := b.Reserve(ctx)
+	assert.Equal(t, epoch.Add(time.Second), r1.Time())
+	assert.Equal(t, epoch.Add(2*time.Second), r2.Time())
+	assert.False(t, r3.OK())
+
+	// Only the most recent reservation can be returned.
+	r1.Cancel()
+	assert.False(t, b.Reserve(ctx).OK())
+	r2.Cancel()
+	assert.Equal(t, epoch.Add(2*time.Second), b.Reserve(ctx).Time())
+
+	f.Advance(3 * time.Second)
+	assert.True(t, b.Allow(ctx))
+
+	full := NewLeakyBucket(time.Second, 0)
+	require.True(t, full.Allow(ctx))
+	assert.Equal(t, ErrLimitExceeded, full.Wait(ctx))
+
+	assert.Panics(t, func() { NewLeakyBucket(0, 1) })
+	assert.Panics(t, func() { NewLeakyBucket(time.Second, -1) })
+}